@@ -0,0 +1,130 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jaycee1285/base16changer/internal/builder"
+	"github.com/jaycee1285/base16changer/internal/scheme"
+)
+
+// runBuild renders tinted-theming template repos for the current scheme,
+// a named one, or every scheme like tinted-builder
+func runBuild(args []string) {
+	fs := flag.NewFlagSet("build", flag.ExitOnError)
+	configPath := fs.String("config", "", "Config file")
+	schemeName := fs.String("scheme", "", "Render this scheme or file instead of the current one")
+	all := fs.Bool("all", false, "Render every scheme in the scheme dirs")
+	output := fs.String("o", "", "Write into this directory (default: into each template repo, like tinted-builder)")
+	dryRun := fs.Bool("dry-run", false, "List the files instead of writing them")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: base16changer build [flags] template-repo...")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 || (*all && *schemeName != "") {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	cfg := loadConfig(*configPath)
+	var repos []*builder.Repo
+	for _, dir := range fs.Args() {
+		repo, err := builder.Load(dir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		repos = append(repos, repo)
+	}
+
+	var schemes []*scheme.Base16
+	failed := false
+	switch {
+	case *all:
+		for _, name := range cfg.ScanSchemes() {
+			s, err := loadScheme(cfg, name)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				failed = true
+				continue
+			}
+			schemes = append(schemes, s)
+		}
+	case *schemeName != "":
+		s, err := loadScheme(cfg, *schemeName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		schemes = append(schemes, s)
+	default:
+		s, err := cfg.CurrentScheme()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v (use --scheme or --all)\n", err)
+			os.Exit(1)
+		}
+		schemes = append(schemes, s)
+	}
+
+	for _, repo := range repos {
+		dir := repo.Dir
+		if *output != "" {
+			dir = *output
+			if len(repos) > 1 {
+				dir = filepath.Join(dir, repo.Name())
+			}
+		}
+		written, supported := 0, false
+		for _, s := range schemes {
+			files, err := repo.Render(s)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s: %s: %v\n", repo.Name(), s.Name, err)
+				failed = true
+				continue
+			}
+			supported = supported || len(files) > 0
+			for _, f := range files {
+				path := filepath.Join(dir, f.Path)
+				if err := writeBuilt(path, f.Content, *dryRun); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					failed = true
+					continue
+				}
+				if !*all {
+					if *dryRun {
+						fmt.Printf("Would write %s\n", path)
+					} else {
+						fmt.Printf("Wrote %s\n", path)
+					}
+				}
+				written++
+			}
+		}
+		if *all {
+			verb := "Built"
+			if *dryRun {
+				verb = "Would build"
+			}
+			fmt.Printf("%s %s: %d files for %d schemes in %s\n", verb, repo.Name(), written, len(schemes), dir)
+		} else if !supported && !failed {
+			fmt.Printf("%s: no template supports %s schemes\n", repo.Name(), schemes[0].SystemName())
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// writeBuilt writes a rendered template, creating its directory
+func writeBuilt(path, content string, dryRun bool) error {
+	if dryRun {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content), 0o644)
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jaycee1285/base16changer/internal/scheme"
+	"github.com/jaycee1285/base16changer/internal/targets"
+)
+
+// runCheck audits the contrast of the text/background pairs the templates
+// use, for the named schemes or every scheme found
+func runCheck(args []string) {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	configPath := fs.String("config", "", "Config file")
+	schemesDir := fs.String("schemes-dir", "", "Only check schemes in this directory")
+	standard := fs.String("standard", string(targets.StandardWCAG), "Contrast metric that decides pass/fail: wcag or apca")
+	verbose := fs.Bool("v", false, "Show every pair, not just failures")
+	jsonOut := fs.Bool("json", false, "Print the results as JSON")
+	fix := fs.Bool("fix", false, "Save a contrast-repaired copy of each named scheme as <name>-accessible.yaml")
+	force := fs.Bool("force", false, "With --fix, replace existing -accessible files")
+	dryRun := fs.Bool("dry-run", false, "With --fix, print the repaired schemes instead of saving them")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: base16changer check [flags] [scheme|file.yaml...]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	std, err := targets.ParseStandard(*standard)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	cfg := loadConfig(*configPath)
+	if *schemesDir != "" {
+		cfg.SchemesDir = *schemesDir
+	}
+
+	cfg.DryRun = *dryRun
+	cfg.Color = useColor()
+
+	names := fs.Args()
+	if *fix {
+		if len(names) == 0 {
+			fmt.Fprintln(os.Stderr, "Error: --fix needs the schemes to repair")
+			os.Exit(1)
+		}
+		runFix(cfg, names, std, *force, *jsonOut)
+		return
+	}
+	if len(names) == 0 {
+		names = cfg.ScanSchemes()
+	}
+
+	reports := []*targets.ContrastReport{}
+	failed := 0
+	for _, name := range names {
+		s, err := loadScheme(cfg, name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			failed++
+			continue
+		}
+		report := targets.CheckContrast(s, std)
+		reports = append(reports, report)
+		if !report.Passed() {
+			failed++
+		}
+		if !*jsonOut {
+			printContrast(name, report, *verbose)
+		}
+	}
+
+	if *jsonOut {
+		data, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+	} else {
+		fmt.Printf("Checked %d scheme(s) against %s: %d pass, %d fail\n",
+			len(names), strings.ToUpper(string(std)), len(names)-failed, failed)
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// fixResult is one scheme of `check --fix --json`
+type fixResult struct {
+	Scheme    string                `json:"scheme"`
+	Path      string                `json:"path,omitempty"`
+	Fixes     []targets.ContrastFix `json:"fixes"`
+	Remaining []targets.PairResult  `json:"remaining,omitempty"`
+	Error     string                `json:"error,omitempty"`
+}
+
+// runFix repairs each scheme's failing pairs and saves the result next to
+// the user's schemes
+func runFix(cfg *targets.Config, names []string, std targets.Standard, force, jsonOut bool) {
+	results := []fixResult{}
+	failed := false
+	for _, name := range names {
+		res := fixResult{Scheme: name, Fixes: []targets.ContrastFix{}}
+		s, err := loadScheme(cfg, name)
+		if err == nil {
+			fixed, fixes, report := targets.RepairContrast(s, std)
+			res.Fixes, res.Remaining = append(res.Fixes, fixes...), report.Failures()
+
+			fixed.Name = s.Name + " (accessible)"
+			fixed.Slug = ""
+			fixed.Description = fmt.Sprintf("%s with contrast raised to %s thresholds", s.Name, strings.ToUpper(string(std)))
+			base := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(name), ".yaml"), ".yml")
+			res.Path, err = cfg.SaveScheme(fixed, base+"-accessible", force)
+		}
+		if err != nil {
+			res.Error = err.Error()
+			failed = true
+		}
+		results = append(results, res)
+		if !jsonOut {
+			printFix(res, cfg.DryRun)
+		}
+	}
+
+	if jsonOut {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func printFix(res fixResult, dryRun bool) {
+	if res.Error != "" {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", res.Scheme, res.Error)
+		return
+	}
+	if len(res.Fixes) == 0 {
+		fmt.Printf("%s: nothing to fix\n", res.Scheme)
+	} else {
+		fmt.Printf("%s:\n", res.Scheme)
+	}
+	for _, f := range res.Fixes {
+		fmt.Printf("  %s  #%s → #%s\n", f.Slot, f.From, f.To)
+	}
+	for _, p := range res.Remaining {
+		fmt.Printf("  still low: %s %s (%s on %s)\n", p.Target, p.Usage, p.FG, p.BG)
+	}
+	if dryRun {
+		fmt.Printf("  would save %s\n", res.Path)
+	} else {
+		fmt.Printf("  saved %s\n", res.Path)
+	}
+}
+
+// loadScheme parses a scheme given by name, or by path when it looks like a file
+func loadScheme(cfg *targets.Config, name string) (*scheme.Base16, error) {
+	path := name
+	if !strings.HasSuffix(name, ".yaml") && !strings.HasSuffix(name, ".yml") {
+		var err error
+		if path, err = cfg.ResolveScheme(name); err != nil {
+			return nil, err
+		}
+	}
+	return cfg.ParseScheme(path)
+}
+
+func printContrast(name string, report *targets.ContrastReport, verbose bool) {
+	failures := report.Failures()
+	if len(failures) == 0 && !verbose {
+		return
+	}
+	if len(failures) == 0 {
+		fmt.Printf("%s: all %d pairs pass\n", name, len(report.Pairs))
+	} else {
+		fmt.Printf("%s: %d of %d pairs fail\n", name, len(failures), len(report.Pairs))
+	}
+
+	pairs := failures
+	if verbose {
+		pairs = report.Pairs
+	}
+	for _, p := range pairs {
+		mark := "✓"
+		if !p.Pass {
+			mark = "✗"
+		}
+		fmt.Printf("  %s %-26s %s on %s  %5.2f:1  Lc %6.1f  (needs %s)\n",
+			mark, p.Target+" "+p.Usage, p.FG, p.BG, p.Ratio, p.Lc, formatRequired(p, report.Standard))
+	}
+}
+
+func formatRequired(p targets.PairResult, std targets.Standard) string {
+	if std == targets.StandardAPCA {
+		return fmt.Sprintf("Lc %.0f", p.Required(std))
+	}
+	return fmt.Sprintf("%.1f:1", p.Required(std))
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jaycee1285/base16changer/internal/convert"
+)
+
+// runExport writes a scheme in another tool's palette format, to stdout or
+// a file
+func runExport(args []string) {
+	var overrides []string
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	configPath := fs.String("config", "", "Config file")
+	format := fs.String("format", "", "Output format: "+convert.FormatList(convert.ExportFormats()))
+	output := fs.String("o", "", "Write to this file, or into this directory as <slug><ext> (default: stdout)")
+	fs.Func("override", "Override one slot before exporting, e.g. base0D=#5e81ac (repeatable)", func(v string) error {
+		overrides = append(overrides, v)
+		return nil
+	})
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: base16changer export --format FORMAT [flags] scheme|file.yaml")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 || *format == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	f, err := convert.ParseExportFormat(*format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	cfg := loadConfig(*configPath)
+	s, err := loadScheme(cfg, fs.Arg(0))
+	if err == nil {
+		err = applyOverrides(s, overrides)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	data, err := convert.Export(s, f)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *output == "" {
+		os.Stdout.Write(data)
+		return
+	}
+
+	path := *output
+	if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+		path = filepath.Join(path, s.SlugName()+convert.Extension(f))
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Exported %s as %s to %s\n", s.Name, f, path)
+}
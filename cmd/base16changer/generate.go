@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jaycee1285/base16changer/internal/palette"
+	"github.com/jaycee1285/base16changer/internal/targets"
+)
+
+// listValue is a repeatable flag that also accepts comma-separated values
+type listValue []string
+
+func (l *listValue) String() string { return strings.Join(*l, ",") }
+
+func (l *listValue) Set(v string) error {
+	*l = append(*l, splitList(v)...)
+	return nil
+}
+
+// runGenerate builds a scheme from seed colors and saves it with the
+// user's schemes, optionally applying it
+func runGenerate(args []string) {
+	var seeds listValue
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	configPath := fs.String("config", "", "Config file")
+	fs.Var(&seeds, "seed", "Seed color (repeatable or comma-separated); the first is the primary")
+	variant := fs.String("variant", palette.VariantDark, "dark or light")
+	strategy := fs.String("strategy", palette.StrategyMaterial, "Palette strategy: "+strings.Join(palette.Strategies, ", "))
+	base24 := fs.Bool("base24", false, "Also generate base10–base17")
+	name := fs.String("name", "", "Scheme name (default: derived from the seed and strategy)")
+	force := fs.Bool("force", false, "Replace an existing scheme with the same name")
+	apply := fs.Bool("apply", false, "Apply the scheme after saving it")
+	dryRun := fs.Bool("dry-run", false, "Show the scheme file (and what applying would change) without writing")
+	jsonOut := fs.Bool("json", false, "With --apply, print the apply report as JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: base16changer generate --seed COLOR [--seed COLOR...] [flags]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if len(seeds) == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	cfg := loadConfig(*configPath)
+	cfg.DryRun = *dryRun
+	cfg.Color = useColor()
+	if *jsonOut {
+		cfg.Quiet = true
+	}
+
+	opts := palette.Options{
+		Name:     *name,
+		Seeds:    seeds,
+		Variant:  *variant,
+		Strategy: *strategy,
+		Base24:   *base24,
+	}
+	if opts.Name == "" {
+		opts.Name = fmt.Sprintf("Seed %s %s %s", strings.TrimPrefix(seeds[0], "#"), *strategy, *variant)
+	}
+	s, err := palette.Generate(opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	saved, err := cfg.SaveScheme(s, s.SlugName(), *force)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if !cfg.Quiet && !cfg.DryRun {
+		fmt.Printf("Saved scheme %s to %s\n", s.SlugName(), saved)
+	}
+	if report := targets.CheckContrast(s, targets.StandardWCAG); !report.Passed() && !cfg.Quiet {
+		fmt.Printf("Note: %d contrast pair(s) below WCAG AA; see `base16changer check %s`\n",
+			len(report.Failures()), s.SlugName())
+	}
+
+	if *apply {
+		applyScheme(cfg, s, *jsonOut)
+	}
+}
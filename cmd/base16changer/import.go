@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jaycee1285/base16changer/internal/convert"
+)
+
+// runImport converts foreign terminal palettes to base16 and saves them
+// with the user's schemes
+func runImport(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	configPath := fs.String("config", "", "Config file")
+	format := fs.String("format", "", "Input format (default: detect): "+convert.FormatList(convert.ImportFormats()))
+	name := fs.String("name", "", "Scheme name (default: the theme's own name or the file name)")
+	force := fs.Bool("force", false, "Replace existing schemes with the same name")
+	dryRun := fs.Bool("dry-run", false, "Print the converted schemes instead of saving them")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: base16changer import [flags] file...")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	var f convert.Format
+	if *format != "" {
+		var err error
+		if f, err = convert.ParseImportFormat(*format); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	cfg := loadConfig(*configPath)
+	cfg.DryRun = *dryRun
+	cfg.Color = useColor()
+
+	failed := false
+	for _, path := range fs.Args() {
+		schemes, err := convert.Import(path, f)
+		if err == nil && *name != "" && len(schemes) > 1 {
+			err = fmt.Errorf("%s holds %d schemes; --name needs a single one", path, len(schemes))
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			failed = true
+			continue
+		}
+
+		for _, s := range schemes {
+			if *name != "" {
+				s.Name = *name
+			}
+			saved, err := cfg.SaveScheme(s, s.SlugName(), *force)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				failed = true
+				continue
+			}
+			if !cfg.DryRun {
+				fmt.Printf("Imported %s as %s (%s)\n", s.Name, s.SlugName(), saved)
+			}
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
@@ -8,13 +9,51 @@ import (
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jaycee1285/base16changer/internal/palette"
 	"github.com/jaycee1285/base16changer/internal/scheme"
 	"github.com/jaycee1285/base16changer/internal/targets"
 	"github.com/jaycee1285/base16changer/internal/ui"
 )
 
 func main() {
+	// Subcommands
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "undo":
+			runUndo(os.Args[2:])
+			return
+		case "rollback":
+			runRollback(os.Args[2:])
+			return
+		case "check":
+			runCheck(os.Args[2:])
+			return
+		case "validate":
+			runValidate(os.Args[2:])
+			return
+		case "show":
+			runShow(os.Args[2:])
+			return
+		case "export":
+			runExport(os.Args[2:])
+			return
+		case "import":
+			runImport(os.Args[2:])
+			return
+		case "generate":
+			runGenerate(os.Args[2:])
+			return
+		case "templates":
+			runTemplates(os.Args[2:])
+			return
+		case "build":
+			runBuild(os.Args[2:])
+			return
+		}
+	}
+
 	var (
+		configPath     string
 		schemeName     string
 		schemePath     string
 		schemesDir     string
@@ -23,18 +62,60 @@ func main() {
 		listFlag       bool
 		listIcons      bool
 		listWallpapers bool
+		listTargets    bool
+		accessible     string
+		fromWallpaper  string
+		variant        string
+		adjust         string
+		tokens         string
+		saveAs         string
+		force          bool
+		overrides      []string
+		near           []string
+		limit          int
 		dryRun         bool
+		noBackup       bool
+		noColor        bool
+		outputDir      string
+		jsonOut        bool
+		only           string
+		skip           string
 	)
 
+	flag.StringVar(&configPath, "config", "", "Config file (default: $XDG_CONFIG_HOME/base16changer/config.yaml)")
 	flag.StringVar(&schemeName, "scheme", "", "Name of the scheme (e.g., gruvbox-dark-medium)")
 	flag.StringVar(&schemePath, "path", "", "Direct path to scheme YAML file")
 	flag.StringVar(&schemesDir, "schemes-dir", "", "Directory containing scheme YAML files")
 	flag.StringVar(&iconTheme, "icon", "", "Icon theme to apply")
-	flag.StringVar(&wallpaper, "wallpaper", "", "Wallpaper filename to apply (from ~/Pictures/walls)")
+	flag.StringVar(&wallpaper, "wallpaper", "", "Wallpaper filename to apply (from the wallpaper directory)")
 	flag.BoolVar(&listFlag, "list", false, "List available schemes")
 	flag.BoolVar(&listIcons, "list-icons", false, "List available icon themes")
 	flag.BoolVar(&listWallpapers, "list-wallpapers", false, "List available wallpapers")
+	flag.BoolVar(&listTargets, "list-targets", false, "List available targets")
+	flag.StringVar(&accessible, "accessible", "", "With --list or --near, only show schemes whose template colors pass the contrast check (wcag or apca)")
+	flag.StringVar(&fromWallpaper, "from-wallpaper", "", "Generate a scheme from this wallpaper (file or name in the wallpaper directory), save it and apply it")
+	flag.StringVar(&variant, "variant", palette.VariantAuto, "With --from-wallpaper: dark, light or auto")
+	flag.Func("override", "Override one slot of the scheme, e.g. base0D=#5e81ac (repeatable)", func(v string) error {
+		overrides = append(overrides, v)
+		return nil
+	})
+	flag.Func("near", "List schemes closest to a color by CIEDE2000, optionally pinned to a slot, e.g. base0D=#83a598 or #1d2021 (repeatable)", func(v string) error {
+		near = append(near, v)
+		return nil
+	})
+	flag.IntVar(&limit, "limit", 10, "With --near, how many schemes to list (0 for all)")
+	flag.StringVar(&adjust, "adjust", "", "Transform the palette before applying, e.g. \"saturation=-15%,temperature=+500K\" (hue, saturation, brightness, temperature, contrast)")
+	flag.StringVar(&tokens, "tokens", "", "Comma-separated design-token formats to write on apply (css, scss, gpl, tokens, nix)")
+	flag.StringVar(&saveAs, "save-as", "", "With --adjust, also save the adjusted palette as a new scheme with this name")
+	flag.BoolVar(&force, "force", false, "With --save-as or --from-wallpaper, replace an existing scheme of that name")
 	flag.BoolVar(&dryRun, "dry-run", false, "Show what would be done without making changes")
+	flag.StringVar(&outputDir, "output-dir", "", "Render all targets into this directory instead of $HOME (no reloads)")
+	flag.StringVar(&outputDir, "root", "", "Alias for --output-dir")
+	flag.BoolVar(&noColor, "no-color", false, "Disable colored diff output")
+	flag.BoolVar(&noBackup, "no-backup", false, "Do not save a backup generation for undo")
+	flag.BoolVar(&jsonOut, "json", false, "Print the apply report (or --list output) as JSON")
+	flag.StringVar(&only, "only", "", "Comma-separated targets to apply (default: all)")
+	flag.StringVar(&skip, "skip", "", "Comma-separated targets to leave untouched")
 	flag.Parse()
 
 	// Also accept scheme name as positional arg
@@ -42,29 +123,98 @@ func main() {
 		schemeName = flag.Args()[0]
 	}
 
-	cfg := targets.DefaultConfig()
+	cfg := loadConfig(configPath)
 	cfg.DryRun = dryRun
-	cfg.IconTheme = iconTheme
-	cfg.Wallpaper = wallpaper
+	cfg.Color = !noColor && useColor()
+	if noBackup {
+		cfg.NoBackup = true
+	}
+	if outputDir != "" {
+		abs, err := filepath.Abs(outputDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		cfg.OutputDir = abs
+	}
+	if iconTheme != "" {
+		cfg.IconTheme = iconTheme
+	}
+	if wallpaper != "" {
+		cfg.Wallpaper = wallpaper
+	}
 	if schemesDir != "" {
 		cfg.SchemesDir = schemesDir
 	}
+	if adjust != "" {
+		adjs, err := scheme.ParseAdjustments(adjust)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		cfg.Adjust = adjs
+	}
+	if tokens != "" {
+		formats, err := targets.ParseTokenFormats(splitList(tokens))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		cfg.TokenFormats = formats
+	}
+	if only != "" {
+		cfg.Only = splitList(only)
+	}
+	if skip != "" {
+		cfg.Skip = splitList(skip)
+	}
+	if _, err := targets.Selected(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	var std targets.Standard
+	if accessible != "" {
+		var err error
+		if std, err = targets.ParseStandard(accessible); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
 	// Handle list commands
 	if listFlag {
-		if schemesDir != "" {
-			listSchemesFromDir(schemesDir)
+		if jsonOut {
+			listSchemesJSON(cfg, std)
+		} else if schemesDir != "" {
+			listSchemesFromDir(cfg, schemesDir, std)
 		} else {
-			listAllSchemes()
+			listAllSchemes(cfg, std)
 		}
 		return
 	}
+	if len(near) > 0 {
+		listNear(cfg, near, limit, std, jsonOut)
+		return
+	}
 	if listIcons {
-		listIconThemes()
+		listIconThemes(cfg)
 		return
 	}
 	if listWallpapers {
-		listWallpaperFiles()
+		listWallpaperFiles(cfg)
+		return
+	}
+	if listTargets {
+		listTargetNames(cfg)
+		return
+	}
+
+	if fromWallpaper != "" {
+		if jsonOut {
+			cfg.Quiet = true
+		}
+		runFromWallpaper(cfg, fromWallpaper, variant, force, jsonOut)
 		return
 	}
 
@@ -75,7 +225,27 @@ func main() {
 	}
 
 	// CLI mode
-	runCLI(cfg, schemeName, schemePath)
+	if jsonOut {
+		cfg.Quiet = true
+	}
+	runCLI(cfg, schemeName, schemePath, overrides, saveAs, force, jsonOut)
+}
+
+// loadConfig returns the default config with the config file applied,
+// exiting on error. An empty path means the default location.
+func loadConfig(path string) *targets.Config {
+	var err error
+	cfg := targets.DefaultConfig()
+	if path != "" {
+		err = targets.LoadConfig(cfg, path, true)
+	} else {
+		err = targets.LoadConfig(cfg, targets.ConfigPath(), false)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	return cfg
 }
 
 func runTUI(cfg *targets.Config) {
@@ -88,98 +258,240 @@ func runTUI(cfg *targets.Config) {
 	}
 }
 
-func runCLI(cfg *targets.Config, schemeName, schemePath string) {
+func runCLI(cfg *targets.Config, schemeName, schemePath string, overrides []string, saveAs string, force, jsonOut bool) {
 	// Resolve scheme path
-	var schemeFile string
-	var err error
-	if schemePath != "" {
-		schemeFile = schemePath
-	} else if cfg.SchemesDir != "" {
-		// Try both extensions
-		for _, ext := range []string{".yaml", ".yml"} {
-			path := filepath.Join(cfg.SchemesDir, schemeName+ext)
-			if _, err := os.Stat(path); err == nil {
-				schemeFile = path
-				break
-			}
-		}
-		if schemeFile == "" {
-			schemeFile = filepath.Join(cfg.SchemesDir, schemeName+".yaml") // fallback for error msg
-		}
-	} else {
-		// Search standard locations
-		schemeFile, err = targets.FindScheme(schemeName)
+	schemeFile := schemePath
+	if schemeFile == "" {
+		var err error
+		schemeFile, err = cfg.ResolveScheme(schemeName)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			fmt.Fprintf(os.Stderr, "Searched: %v\n", targets.SchemesDirs())
 			os.Exit(1)
 		}
 	}
 
 	// Parse scheme
-	s, err := scheme.Parse(schemeFile)
+	s, err := cfg.ParseScheme(schemeFile)
+	if err == nil {
+		err = applyOverrides(s, overrides)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Apply
-	if err := targets.Apply(cfg, s); err != nil {
+	if saveAs != "" {
+		s = saveAdjusted(cfg, s, saveAs, force)
+	}
+	applyScheme(cfg, s, jsonOut)
+}
+
+// saveAdjusted saves s with cfg.Adjust applied as a new scheme called name
+// and returns it; the adjustments are cleared so Apply does not repeat them
+func saveAdjusted(cfg *targets.Config, s *scheme.Base16, name string, force bool) *scheme.Base16 {
+	if len(cfg.Adjust) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: --save-as needs --adjust (or defaults.adjust in the config)")
+		os.Exit(1)
+	}
+	adjusted := s.Adjust(cfg.Adjust)
+	adjusted.Name = name
+	adjusted.Slug = ""
+	adjusted.Description = fmt.Sprintf("%s adjusted: %s", s.Name, scheme.FormatAdjustments(cfg.Adjust))
+
+	saved, err := cfg.SaveScheme(adjusted, adjusted.SlugName(), force)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if !cfg.Quiet && !cfg.DryRun {
+		fmt.Printf("Saved scheme %s to %s\n", adjusted.SlugName(), saved)
+	}
+	cfg.Adjust = nil
+	return adjusted
+}
+
+// runFromWallpaper generates a scheme from a wallpaper, saves it to the
+// user scheme dir and applies it together with the wallpaper. An existing
+// scheme of the same name is only replaced with force.
+func runFromWallpaper(cfg *targets.Config, wallpaper, variant string, force, jsonOut bool) {
+	path := wallpaper
+	if _, err := os.Stat(path); err != nil {
+		path = filepath.Join(cfg.WallpaperDir, wallpaper)
+	}
+
+	s, err := palette.FromWallpaper(path, variant)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	saved, err := cfg.SaveScheme(s, s.SlugName(), force)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if !cfg.Quiet && !cfg.DryRun {
+		fmt.Printf("Saved scheme %s to %s\n", s.SlugName(), saved)
+	}
+
+	if cfg.Wallpaper == "" {
+		if abs, err := filepath.Abs(path); err == nil {
+			cfg.Wallpaper = abs
+		}
+	}
+	applyScheme(cfg, s, jsonOut)
+}
+
+// applyScheme applies s and exits through finish
+func applyScheme(cfg *targets.Config, s *scheme.Base16, jsonOut bool) {
+	report, err := targets.Apply(cfg, s)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error applying scheme: %v\n", err)
 		os.Exit(1)
 	}
 
-	fmt.Println("\nDone!")
+	finish(report, jsonOut)
 }
 
-func listAllSchemes() {
-	schemes := targets.ScanAllSchemes()
-	fmt.Printf("Available schemes (from %v):\n\n", targets.SchemesDirs())
+// finish prints the report summary (or JSON) and exits non-zero on failure
+func finish(report *targets.Report, jsonOut bool) {
+	if jsonOut {
+		data, err := report.JSON()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+	} else if report.Failed() {
+		fmt.Printf("\nFinished with errors: %s\n", report.Summary())
+	} else {
+		fmt.Printf("\nDone! (%s)\n", report.Summary())
+	}
+
+	if report.Failed() {
+		os.Exit(1)
+	}
+}
+
+func listAllSchemes(cfg *targets.Config, std targets.Standard) {
+	schemes := passingSchemes(cfg, targets.ScanAllSchemes(cfg.SchemeDirs), std)
+	fmt.Printf("Available schemes (from %v):\n\n", cfg.SchemeDirs)
 	printColumns(schemes, 3)
 	fmt.Printf("\nTotal: %d schemes\n", len(schemes))
 }
 
-func listSchemesFromDir(dir string) {
-	entries, err := os.ReadDir(dir)
+func listSchemesFromDir(cfg *targets.Config, dir string, std targets.Standard) {
+	schemes, err := targets.ScanSchemesDir(dir)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error reading schemes directory: %v\n", err)
 		fmt.Fprintf(os.Stderr, "Expected: %s\n", dir)
 		os.Exit(1)
 	}
+	schemes = passingSchemes(cfg, schemes, std)
 
 	fmt.Printf("Available schemes in %s:\n\n", dir)
+	printColumns(schemes, 3)
+	fmt.Printf("\nTotal: %d schemes\n", len(schemes))
+}
+
+// schemeInfo is one entry of `--list --json`
+type schemeInfo struct {
+	Name    string        `json:"name"`
+	Path    string        `json:"path"`
+	Format  scheme.Format `json:"format,omitempty"`
+	System  string        `json:"system,omitempty"`
+	Variant string        `json:"variant,omitempty"`
+	Error   string        `json:"error,omitempty"`
+}
 
-	var schemes []string
-	for _, e := range entries {
-		if e.IsDir() {
-			continue
+// listSchemesJSON prints every visible scheme with its detected file format
+func listSchemesJSON(cfg *targets.Config, std targets.Standard) {
+	infos := []schemeInfo{}
+	for _, name := range passingSchemes(cfg, cfg.ScanSchemes(), std) {
+		info := schemeInfo{Name: name}
+		path, err := cfg.ResolveScheme(name)
+		if err == nil {
+			info.Path = path
+			var s *scheme.Base16
+			if s, err = cfg.ParseScheme(path); err == nil {
+				info.Format = s.Format
+				info.System = s.SystemName()
+				info.Variant = s.VariantName()
+			}
 		}
-		name := e.Name()
-		if strings.HasSuffix(name, ".yaml") {
-			schemes = append(schemes, strings.TrimSuffix(name, ".yaml"))
-		} else if strings.HasSuffix(name, ".yml") {
-			schemes = append(schemes, strings.TrimSuffix(name, ".yml"))
+		if err != nil {
+			info.Error = err.Error()
 		}
+		infos = append(infos, info)
 	}
 
-	printColumns(schemes, 3)
-	fmt.Printf("\nTotal: %d schemes\n", len(schemes))
+	data, err := json.MarshalIndent(infos, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(string(data))
 }
 
-func listIconThemes() {
-	icons := targets.ScanIconThemes()
-	fmt.Println("Available icon themes:\n")
+// passingSchemes keeps the schemes that parse and pass the contrast check
+// under std; an empty std keeps every scheme
+func passingSchemes(cfg *targets.Config, names []string, std targets.Standard) []string {
+	if std == "" {
+		return names
+	}
+	var out []string
+	for _, name := range names {
+		s, err := loadScheme(cfg, name)
+		if err == nil && targets.CheckContrast(s, std).Passed() {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+func listIconThemes(cfg *targets.Config) {
+	icons := targets.ScanIconThemes(cfg.IconDirs)
+	fmt.Print("Available icon themes:\n\n")
 	printColumns(icons, 3)
 	fmt.Printf("\nTotal: %d icon themes\n", len(icons))
 }
 
-func listWallpaperFiles() {
-	walls := targets.ScanWallpapers()
-	fmt.Printf("Available wallpapers in %s:\n\n", targets.WallpaperDir())
+func listWallpaperFiles(cfg *targets.Config) {
+	walls := targets.ScanWallpapers(cfg.WallpaperDir)
+	fmt.Printf("Available wallpapers in %s:\n\n", cfg.WallpaperDir)
 	printColumns(walls, 2)
 	fmt.Printf("\nTotal: %d wallpapers\n", len(walls))
 }
 
+func listTargetNames(cfg *targets.Config) {
+	fmt.Print("Available targets (in apply order):\n\n")
+	for _, name := range targets.Names() {
+		fmt.Println("  " + name)
+	}
+	for _, t := range cfg.Custom {
+		fmt.Printf("  %-14s custom: %s\n", t.Name(), t.Output)
+	}
+}
+
+// useColor reports whether stdout is a terminal and NO_COLOR is unset
+func useColor() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	fi, err := os.Stdout.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
 func printColumns(items []string, cols int) {
 	for i, s := range items {
 		fmt.Printf("%-35s", s)
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jaycee1285/base16changer/internal/scheme"
+	"github.com/jaycee1285/base16changer/internal/targets"
+)
+
+// listNear ranks the visible schemes by CIEDE2000 distance to the --near
+// colors and prints the closest, with the distance of each color
+func listNear(cfg *targets.Config, specs []string, limit int, std targets.Standard, jsonOut bool) {
+	var colors []scheme.NearColor
+	for _, spec := range specs {
+		n, err := scheme.ParseNear(spec)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		colors = append(colors, n)
+	}
+
+	matches := cfg.RankNear(passingSchemes(cfg, cfg.ScanSchemes(), std), colors)
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	if jsonOut {
+		if matches == nil {
+			matches = []targets.NearMatch{}
+		}
+		data, err := json.MarshalIndent(matches, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	var query []string
+	for _, n := range colors {
+		query = append(query, n.String())
+	}
+	fmt.Printf("Schemes closest to %s (CIEDE2000):\n\n", strings.Join(query, ", "))
+	color := useColor()
+	for i, m := range matches {
+		fmt.Printf("%3d. %-32s ΔE %5.1f\n", i+1, m.Name, m.Score)
+		for _, d := range m.Slots {
+			swatch := ""
+			if color {
+				r, g, b := hexRGB(d.Hex)
+				swatch = fmt.Sprintf("\x1b[48;2;%d;%d;%dm  \x1b[0m ", r, g, b)
+			}
+			fmt.Printf("       %-22s %s%-7s #%s  ΔE %5.1f\n", d.Query, swatch, d.Slot, d.Hex, d.Distance)
+		}
+	}
+	if len(matches) == 0 {
+		fmt.Println("No schemes found")
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jaycee1285/base16changer/internal/scheme"
+)
+
+// showResult is the JSON form of `show`
+type showResult struct {
+	Name        string        `json:"name"`
+	Slug        string        `json:"slug"`
+	Path        string        `json:"path"`
+	Format      scheme.Format `json:"format"`
+	System      string        `json:"system"`
+	Variant     string        `json:"variant"`
+	Author      string        `json:"author,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Chain       []string      `json:"chain"`
+	Palette     []showSlot    `json:"palette"`
+}
+
+type showSlot struct {
+	Slot     string `json:"slot"`
+	Hex      string `json:"hex"`
+	Origin   string `json:"origin,omitempty"`   // file or --override the color came from
+	Fallback string `json:"fallback,omitempty"` // base16 slot standing in for an unset base24 slot
+}
+
+// runShow prints a scheme's metadata, the chain of schemes it extends and
+// where each color came from
+func runShow(args []string) {
+	var overrides []string
+	fs := flag.NewFlagSet("show", flag.ExitOnError)
+	configPath := fs.String("config", "", "Config file")
+	schemesDir := fs.String("schemes-dir", "", "Look up the scheme (and what it extends) in this directory")
+	fs.Func("override", "Override one slot, e.g. base0D=#5e81ac (repeatable)", func(v string) error {
+		overrides = append(overrides, v)
+		return nil
+	})
+	jsonOut := fs.Bool("json", false, "Print the scheme as JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: base16changer show [flags] scheme|file.yaml")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	cfg := loadConfig(*configPath)
+	if *schemesDir != "" {
+		cfg.SchemesDir = *schemesDir
+	}
+	s, err := loadScheme(cfg, fs.Arg(0))
+	if err == nil {
+		err = applyOverrides(s, overrides)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	res := showResult{
+		Name:        s.Name,
+		Slug:        s.SlugName(),
+		Path:        s.Chain[0],
+		Format:      s.Format,
+		System:      s.SystemName(),
+		Variant:     s.VariantName(),
+		Author:      s.Author,
+		Description: s.Description,
+		Chain:       s.Chain,
+	}
+	for _, name := range scheme.Base24Slots {
+		slot := showSlot{Slot: name, Hex: s.Palette.Hex(name), Origin: s.Origin(name)}
+		if slot.Origin == "" {
+			slot.Fallback = scheme.FallbackSlot(name)
+		}
+		res.Palette = append(res.Palette, slot)
+	}
+
+	if *jsonOut {
+		data, err := json.MarshalIndent(res, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+		return
+	}
+	printShow(res, useColor())
+}
+
+// applyOverrides applies --override slot=color specs in order
+func applyOverrides(s *scheme.Base16, overrides []string) error {
+	for _, o := range overrides {
+		if err := s.Override(o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printShow(res showResult, color bool) {
+	fmt.Println(res.Name)
+	fmt.Printf("  File:     %s\n", res.Path)
+	fmt.Printf("  Format:   %s %s, %s\n", res.Format, res.System, res.Variant)
+	if res.Author != "" {
+		fmt.Printf("  Author:   %s\n", res.Author)
+	}
+	if res.Description != "" {
+		fmt.Printf("  About:    %s\n", res.Description)
+	}
+	if len(res.Chain) > 1 {
+		names := make([]string, len(res.Chain))
+		for i, p := range res.Chain {
+			names[i] = filepath.Base(p)
+		}
+		fmt.Printf("  Extends:  %s\n", strings.Join(names, " → "))
+		for _, p := range res.Chain[1:] {
+			fmt.Printf("            %s\n", p)
+		}
+	}
+	fmt.Println()
+
+	for _, slot := range res.Palette {
+		swatch := ""
+		if color {
+			r, g, b := hexRGB(slot.Hex)
+			swatch = fmt.Sprintf("\x1b[48;2;%d;%d;%dm    \x1b[0m  ", r, g, b)
+		}
+		from := filepath.Base(slot.Origin)
+		switch {
+		case slot.Origin == scheme.OriginOverride:
+			from = slot.Origin
+		case slot.Origin == "":
+			from = "(" + slot.Fallback + ")"
+		}
+		fmt.Printf("  %s  %s  %s%s\n", slot.Slot, slot.Hex, swatch, from)
+	}
+}
+
+// hexRGB splits a #rrggbb color into its channels
+func hexRGB(hex string) (r, g, b int) {
+	fmt.Sscanf(strings.TrimPrefix(hex, "#"), "%02x%02x%02x", &r, &g, &b)
+	return r, g, b
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jaycee1285/base16changer/internal/targets"
+)
+
+// runTemplates lists the built-in templates or copies them out for editing
+func runTemplates(args []string) {
+	usage := func() {
+		fmt.Fprintln(os.Stderr, "Usage: base16changer templates list|dump [flags] [template...]")
+	}
+	if len(args) == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet("templates "+args[0], flag.ExitOnError)
+	configPath := fs.String("config", "", "Config file")
+	force := fs.Bool("force", false, "With dump, replace templates already in the directory")
+	dryRun := fs.Bool("dry-run", false, "With dump, print the templates instead of writing them")
+	fs.Parse(args[1:])
+
+	cfg := loadConfig(*configPath)
+	switch args[0] {
+	case "list":
+		fmt.Printf("Built-in templates (overrides in %s):\n\n", cfg.TemplatesDir)
+		for _, name := range targets.BuiltinTemplates() {
+			if path := cfg.TemplateOverride(name); path != "" {
+				fmt.Printf("  %-22s overridden by %s\n", name, path)
+			} else {
+				fmt.Printf("  %-22s built-in\n", name)
+			}
+		}
+	case "dump":
+		cfg.DryRun = *dryRun
+		cfg.Color = useColor()
+		names, err := templateNames(fs.Args())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		failed := false
+		for _, name := range names {
+			path, err := cfg.DumpTemplate(name, *force)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				failed = true
+				continue
+			}
+			if !cfg.DryRun {
+				fmt.Printf("Wrote %s\n", path)
+			}
+		}
+		if failed {
+			os.Exit(1)
+		}
+	default:
+		usage()
+		os.Exit(2)
+	}
+}
+
+// templateNames resolves template arguments ("kitty" or "kitty.mustache")
+// to built-in names; none means all of them
+func templateNames(args []string) ([]string, error) {
+	builtins := targets.BuiltinTemplates()
+	if len(args) == 0 {
+		return builtins, nil
+	}
+	var names []string
+	for _, a := range args {
+		name := strings.TrimSuffix(a, ".mustache") + ".mustache"
+		found := false
+		for _, b := range builtins {
+			if b == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("no built-in template %q (available: %s)", a, strings.Join(builtins, ", "))
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
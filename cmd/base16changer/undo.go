@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jaycee1285/base16changer/internal/targets"
+)
+
+// runUndo reverts the most recent apply
+func runUndo(args []string) {
+	fs := flag.NewFlagSet("undo", flag.ExitOnError)
+	configPath := fs.String("config", "", "Config file")
+	dryRun := fs.Bool("dry-run", false, "Show what would be restored without making changes")
+	jsonOut := fs.Bool("json", false, "Print the report as JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: base16changer undo [flags]")
+		fmt.Fprintln(fs.Output(), "Reverts the last apply. The generation is deleted, so an undo cannot be undone.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	cfg := loadConfig(*configPath)
+	cfg.DryRun = *dryRun
+	cfg.Quiet = *jsonOut
+
+	report, err := targets.Undo(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	finish(report, *jsonOut)
+}
+
+// runRollback restores the files of an earlier generation, or lists
+// generations when --to is not given
+func runRollback(args []string) {
+	fs := flag.NewFlagSet("rollback", flag.ExitOnError)
+	configPath := fs.String("config", "", "Config file")
+	to := fs.Int("to", -1, "Generation to roll back to (0 = before the oldest saved run)")
+	dryRun := fs.Bool("dry-run", false, "Show what would be restored without making changes")
+	jsonOut := fs.Bool("json", false, "Print the report as JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: base16changer rollback [--to N] [flags]")
+		fmt.Fprintln(fs.Output(), "Lists generations, or reverts every generation newer than N and deletes them.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *to < 0 {
+		listGenerations()
+		return
+	}
+
+	cfg := loadConfig(*configPath)
+	cfg.DryRun = *dryRun
+	cfg.Quiet = *jsonOut
+
+	report, err := targets.Rollback(cfg, *to)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	finish(report, *jsonOut)
+}
+
+func listGenerations() {
+	gens, err := targets.ListGenerations()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(gens) == 0 {
+		fmt.Println("No generations saved.")
+		return
+	}
+
+	fmt.Print("Saved generations (roll back with --to N):\n\n")
+	for _, g := range gens {
+		fmt.Printf("  %4d  %s  %-30s %d file(s)\n", g.ID, g.Time.Format("2006-01-02 15:04:05"), g.Scheme, len(g.Files))
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/jaycee1285/base16changer/internal/scheme"
+	"github.com/jaycee1285/base16changer/internal/targets"
+)
+
+// validationResult is one file of `validate --json`
+type validationResult struct {
+	Path   string          `json:"path"`
+	Format scheme.Format   `json:"format,omitempty"`
+	Errors []validationErr `json:"errors,omitempty"`
+}
+
+type validationErr struct {
+	Line    int    `json:"line,omitempty"`
+	Slot    string `json:"slot,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Message string `json:"message"`
+}
+
+// runValidate parses every scheme file under the given files and
+// directories (default: the configured scheme dirs) and reports the
+// invalid ones
+func runValidate(args []string) {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	configPath := fs.String("config", "", "Config file")
+	jsonOut := fs.Bool("json", false, "Print the results as JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: base16changer validate [flags] [file|dir...]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	cfg := loadConfig(*configPath)
+	paths := fs.Args()
+	if len(paths) == 0 {
+		for _, dir := range cfg.SchemeDirs {
+			if _, err := os.Stat(dir); err == nil {
+				paths = append(paths, dir)
+			}
+		}
+	}
+	files, err := targets.SchemeFiles(paths)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	results := []validationResult{}
+	invalid := 0
+	for _, path := range files {
+		res := validationResult{Path: path}
+		s, err := cfg.ParseScheme(path)
+		var verr *scheme.ValidationError
+		switch {
+		case errors.As(err, &verr):
+			for _, se := range verr.Errors {
+				res.Errors = append(res.Errors, validationErr{Line: se.Line, Slot: se.Slot, Value: se.Value, Message: se.Err.Error()})
+			}
+		case err != nil:
+			res.Errors = append(res.Errors, validationErr{Message: err.Error()})
+		default:
+			res.Format = s.Format
+		}
+		if err != nil {
+			invalid++
+			if !*jsonOut {
+				fmt.Println(err)
+			}
+		}
+		results = append(results, res)
+	}
+
+	if *jsonOut {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+	} else {
+		fmt.Printf("Checked %d scheme(s): %d valid, %d invalid\n", len(files), len(files)-invalid, invalid)
+	}
+	if invalid > 0 {
+		os.Exit(1)
+	}
+}
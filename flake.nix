@@ -20,7 +20,7 @@
             pname = "base16changer";
             version = "0.1.0";
             src = ./.;
-            vendorHash = "sha256-X7tnknsKwkYmWMHZUyQgEE/Ez/boQdfIEvwLcg0JmUI=";
+            vendorHash = "sha256-KzCKhXaNUoMNs9cI55hq6rjOK1+pI/RpYQxN3hYDpMA=";
 
             meta = {
               description = "Base16 theme switcher with hot-reload for labwc, kitty, fuzzel, GTK";
@@ -0,0 +1,175 @@
+// Package builder renders tinted-theming template repositories
+// (base16-vim, base16-tmux, tinted-shell …) the way tinted-builder does.
+//
+// A template repo has a templates/config.yaml whose keys name mustache
+// files in templates/. Each entry says where the rendered file goes,
+// either with a filename template
+//
+//	default:
+//	  filename: "colors/{{ scheme-system }}-{{ scheme-slug }}.vim"
+//	  supported-systems: [base16, base24]
+//
+// or, in the older layout, with an output directory and an extension,
+// which become <output>/<system>-<slug><extension>:
+//
+//	default:
+//	  extension: .vim
+//	  output: colors
+package builder
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jaycee1285/base16changer/internal/scheme"
+	"github.com/jaycee1285/base16changer/internal/template"
+)
+
+// ConfigPath is where a template repo lists its templates
+const ConfigPath = "templates/config.yaml"
+
+// Template is one entry of a repo's templates/config.yaml
+type Template struct {
+	Name      string // renders templates/<Name>.mustache
+	Filename  string // output path template, relative to the output dir
+	Output    string // older layout: <Output>/<system>-<slug><Extension>
+	Extension string
+	Systems   []string // supported-systems; base16 when unset
+}
+
+// Repo is a local checkout of a template repository
+type Repo struct {
+	Dir       string
+	Templates []Template
+}
+
+// File is a rendered template; Path is relative to the output dir
+type File struct {
+	Template string
+	Path     string
+	Content  string
+}
+
+// templateConfig mirrors one entry of templates/config.yaml
+type templateConfig struct {
+	Filename         string   `yaml:"filename"`
+	Extension        string   `yaml:"extension"`
+	Output           string   `yaml:"output"`
+	SupportedSystems []string `yaml:"supported-systems"`
+}
+
+// Load reads a template repo's templates/config.yaml. Templates are
+// sorted by name.
+func Load(dir string) (*Repo, error) {
+	path := filepath.Join(dir, ConfigPath)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%s is not a template repo: no %s", dir, ConfigPath)
+		}
+		return nil, err
+	}
+	var entries map[string]templateConfig
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%s: no templates", path)
+	}
+
+	repo := &Repo{Dir: dir}
+	var errs []string
+	for name, e := range entries {
+		t := Template{
+			Name:      name,
+			Filename:  strings.TrimSpace(e.Filename),
+			Extension: strings.TrimSpace(e.Extension),
+			Output:    strings.TrimSpace(e.Output),
+			Systems:   e.SupportedSystems,
+		}
+		if t.Filename == "" && t.Extension == "" {
+			errs = append(errs, fmt.Sprintf("%s: needs filename, or output and extension", name))
+			continue
+		}
+		if len(t.Systems) == 0 {
+			t.Systems = []string{"base16"}
+		}
+		if _, err := os.Stat(t.source(dir)); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		repo.Templates = append(repo.Templates, t)
+	}
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return nil, fmt.Errorf("%s:\n  %s", path, strings.Join(errs, "\n  "))
+	}
+	sort.Slice(repo.Templates, func(i, j int) bool { return repo.Templates[i].Name < repo.Templates[j].Name })
+	return repo, nil
+}
+
+// Name is the repo's directory name, such as base16-vim
+func (r *Repo) Name() string {
+	return filepath.Base(filepath.Clean(r.Dir))
+}
+
+// source is the template's mustache file
+func (t Template) source(dir string) string {
+	return filepath.Join(dir, "templates", t.Name+".mustache")
+}
+
+// Supports reports whether the template renders schemes of a system
+func (t Template) Supports(system string) bool {
+	for _, s := range t.Systems {
+		if s == system {
+			return true
+		}
+	}
+	return false
+}
+
+// Render renders every template that supports the scheme's system.
+// Templates for other systems are skipped, as tinted-builder does.
+func (r *Repo) Render(s *scheme.Base16) ([]File, error) {
+	data := s.ToMap()
+	var files []File
+	for _, t := range r.Templates {
+		if !t.Supports(s.SystemName()) {
+			continue
+		}
+		path, err := t.outputPath(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", t.Name, err)
+		}
+		content, err := template.Render(t.source(r.Dir), data)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Template: t.Name, Path: path, Content: content})
+	}
+	return files, nil
+}
+
+// outputPath renders the template's filename for a scheme. The result
+// must stay inside the output dir.
+func (t Template) outputPath(data map[string]string) (string, error) {
+	var path string
+	if t.Filename != "" {
+		var err error
+		if path, err = template.RenderString(t.Filename, data); err != nil {
+			return "", fmt.Errorf("filename: %w", err)
+		}
+	} else {
+		path = filepath.Join(t.Output, data["scheme-system"]+"-"+data["scheme-slug"]+t.Extension)
+	}
+	path = filepath.Clean(strings.TrimSpace(path))
+	if path == "." || filepath.IsAbs(path) || path == ".." || strings.HasPrefix(path, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("output %q is outside the output directory", path)
+	}
+	return path, nil
+}
@@ -0,0 +1,170 @@
+package convert
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jaycee1285/base16changer/internal/scheme"
+)
+
+// alacrittyColors are the color names of alacritty's [colors.normal] and
+// [colors.bright] tables, in ANSI order
+var alacrittyColors = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+func sniffAlacritty(data []byte) bool {
+	return hasAny(data, "[colors.primary]", "[colors.normal]", "colors.normal.")
+}
+
+func sniffAlacrittyYAML(data []byte) bool {
+	return hasAny(data, "colors:") && hasAny(data, "normal:") && hasAny(data, "primary:")
+}
+
+// parseAlacritty reads the colors of an alacritty.toml theme
+func parseAlacritty(data []byte) ([]*scheme.Gogh, error) {
+	values, err := parseTOMLStrings(data)
+	if err != nil {
+		return nil, err
+	}
+	return alacrittyTheme(values), nil
+}
+
+// parseAlacrittyYAML reads the colors of a pre-0.13 alacritty.yml theme
+func parseAlacrittyYAML(data []byte) ([]*scheme.Gogh, error) {
+	var root map[string]any
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	values := map[string]string{}
+	flatten("", root, values)
+	return alacrittyTheme(values), nil
+}
+
+// alacrittyTheme maps dotted keys (colors.normal.red …) to a palette
+func alacrittyTheme(values map[string]string) []*scheme.Gogh {
+	if len(values) == 0 {
+		return nil
+	}
+	g := &scheme.Gogh{
+		Background: hexColor(values["colors.primary.background"]),
+		Foreground: hexColor(values["colors.primary.foreground"]),
+		Cursor:     hexColor(values["colors.cursor.cursor"]),
+	}
+	ansi := g.ANSI()
+	for i, name := range alacrittyColors {
+		*ansi[i] = hexColor(values["colors.normal."+name])
+		*ansi[i+8] = hexColor(values["colors.bright."+name])
+	}
+	return []*scheme.Gogh{g}
+}
+
+// flatten collects the scalar leaves of a decoded YAML document under
+// dotted keys
+func flatten(prefix string, v any, out map[string]string) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			flatten(prefix+k+".", child, out)
+		}
+	case nil:
+	case int:
+		out[strings.TrimSuffix(prefix, ".")] = fmt.Sprintf("0x%06x", v) // unquoted 0x123456
+	default:
+		out[strings.TrimSuffix(prefix, ".")] = fmt.Sprint(v)
+	}
+}
+
+// parseTOMLStrings reads the string values of a TOML document under their
+// dotted keys. It understands [table] headers, dotted keys, one-line
+// inline tables and comments, which is all color themes use; other value
+// types, and multi-line arrays in full configs, are skipped.
+func parseTOMLStrings(data []byte) (map[string]string, error) {
+	out := map[string]string{}
+	table := ""
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(stripTOMLComment(sc.Text()))
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "[["):
+			table = "" // arrays of tables hold no colors
+			continue
+		case strings.HasPrefix(line, "["):
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: unterminated table header", n)
+			}
+			table = tomlKey(line[1:len(line)-1]) + "."
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue // continuation of a multi-line array
+		}
+		tomlValue(table+tomlKey(key), strings.TrimSpace(value), out)
+	}
+	return out, sc.Err()
+}
+
+// tomlValue stores a string value, or the string values of an inline table
+func tomlValue(key, value string, out map[string]string) {
+	switch {
+	case strings.HasPrefix(value, "{") && strings.HasSuffix(value, "}"):
+		for _, pair := range strings.Split(value[1:len(value)-1], ",") {
+			if k, v, ok := strings.Cut(pair, "="); ok {
+				tomlValue(key+"."+tomlKey(k), strings.TrimSpace(v), out)
+			}
+		}
+	case len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0]:
+		out[key] = value[1 : len(value)-1]
+	}
+}
+
+// tomlKey normalizes a possibly dotted, quoted key
+func tomlKey(key string) string {
+	parts := strings.Split(strings.TrimSpace(key), ".")
+	for i, p := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(p), `"'`)
+	}
+	return strings.Join(parts, ".")
+}
+
+// stripTOMLComment drops a # comment that is not inside a string
+func stripTOMLComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// exportAlacritty writes the [colors] tables of an alacritty.toml theme
+func exportAlacritty(s *scheme.Base16) ([]byte, error) {
+	g := s.ToGogh()
+	ansi := g.ANSI()
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n# Generated by base16changer\n\n", s.Name)
+	fmt.Fprintf(&b, "[colors.primary]\nbackground = %q\nforeground = %q\n\n", g.Background, g.Foreground)
+	fmt.Fprintf(&b, "[colors.cursor]\ntext = %q\ncursor = %q\n\n", g.Background, g.Cursor)
+	fmt.Fprintf(&b, "[colors.selection]\ntext = %q\nbackground = %q\n", s.Palette.Hex("base05"), s.Palette.Hex("base02"))
+	for i, table := range []string{"normal", "bright"} {
+		fmt.Fprintf(&b, "\n[colors.%s]\n", table)
+		for j, name := range alacrittyColors {
+			fmt.Fprintf(&b, "%s = %q\n", name, *ansi[i*8+j])
+		}
+	}
+	return []byte(b.String()), nil
+}
@@ -0,0 +1,243 @@
+// Package convert translates between base16 schemes and the palette
+// formats of other terminals and tools.
+package convert
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/lucasb-eyer/go-colorful"
+
+	"github.com/jaycee1285/base16changer/internal/scheme"
+)
+
+// Format names a foreign palette format
+type Format string
+
+const (
+	FormatITerm2          Format = "iterm2"           // .itermcolors plist
+	FormatAlacritty       Format = "alacritty"        // alacritty.toml [colors.*] tables
+	FormatAlacrittyYAML   Format = "alacritty-yaml"   // pre-0.13 alacritty.yml
+	FormatWindowsTerminal Format = "windows-terminal" // settings.json scheme objects
+	FormatXresources      Format = "xresources"       // *.color0 … resources
+	FormatKitty           Format = "kitty"            // kitty.conf color0 … lines
+	FormatGogh            Format = "gogh"             // Gogh YAML theme
+	FormatPywal           Format = "pywal"            // pywal colors.json
+)
+
+// importer reads every theme in a file of one format as ANSI palettes.
+// Themes without a name are named after the file.
+type importer struct {
+	format Format
+	exts   []string               // lowercase file extensions, with dot
+	sniff  func(data []byte) bool // recognizes the format by content
+	parse  func(data []byte) ([]*scheme.Gogh, error)
+}
+
+// importers is ordered so that sniffing tries the most specific formats
+// first
+var importers = []importer{
+	{FormatITerm2, []string{".itermcolors"}, sniffITerm2, parseITerm2},
+	{FormatWindowsTerminal, []string{".json"}, sniffWindowsTerminal, parseWindowsTerminal},
+	{FormatAlacritty, []string{".toml"}, sniffAlacritty, parseAlacritty},
+	{FormatAlacrittyYAML, []string{".yml", ".yaml"}, sniffAlacrittyYAML, parseAlacrittyYAML},
+	{FormatXresources, []string{".xresources", ".xdefaults", ".ad"}, sniffXresources, parseXresources},
+	{FormatKitty, []string{".conf"}, sniffKitty, parseKitty},
+	{FormatGogh, []string{".yml", ".yaml"}, sniffGogh, parseGogh},
+	{FormatPywal, []string{".json"}, sniffPywal, parsePywal},
+}
+
+// ImportFormats lists the formats Import accepts
+func ImportFormats() []Format {
+	out := make([]Format, len(importers))
+	for i, imp := range importers {
+		out[i] = imp.format
+	}
+	return out
+}
+
+// ParseImportFormat checks a format name against the importable formats
+func ParseImportFormat(name string) (Format, error) {
+	for _, f := range ImportFormats() {
+		if string(f) == name {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown import format %q (expected one of: %s)", name, FormatList(ImportFormats()))
+}
+
+// Import reads the themes in a foreign palette file and converts each to
+// base16 the way Gogh themes are: the ANSI colors fill the accents and
+// base24 brights, orange and brown are derived, and the background ramp is
+// interpolated between background and foreground. An empty format is
+// detected from the file extension, then the contents.
+func Import(path string, format Format) ([]*scheme.Base16, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	imp, err := findImporter(path, data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	themes, err := imp.parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(themes) == 0 {
+		return nil, fmt.Errorf("%s: no %s color scheme found", path, imp.format)
+	}
+
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if base == "" {
+		base = strings.TrimPrefix(filepath.Base(path), ".") // .Xresources
+	}
+	var out []*scheme.Base16
+	for _, g := range themes {
+		if g.Name == "" {
+			g.Name = base
+		}
+		if err := complete(g); err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", path, g.Name, err)
+		}
+		if err := g.Validate(); err != nil {
+			var verr *scheme.ValidationError
+			if errors.As(err, &verr) {
+				verr.File = path
+			}
+			return nil, err
+		}
+		if g.Variant == "" {
+			g.Variant = variantOf(g.Background)
+		}
+
+		s := g.ToBase16()
+		s.Format = ""
+		s.Description = fmt.Sprintf("Imported from %s (%s)", filepath.Base(path), imp.format)
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// findImporter picks the importer for format, or detects one
+func findImporter(path string, data []byte, format Format) (importer, error) {
+	if format != "" {
+		for _, imp := range importers {
+			if imp.format == format {
+				return imp, nil
+			}
+		}
+		return importer{}, fmt.Errorf("unknown import format %q (expected one of: %s)", format, FormatList(ImportFormats()))
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	if strings.HasPrefix(strings.ToLower(filepath.Base(path)), ".xresources") ||
+		strings.HasPrefix(strings.ToLower(filepath.Base(path)), "xresources") {
+		ext = ".xresources"
+	}
+	for _, imp := range importers {
+		for _, e := range imp.exts {
+			if e == ext && imp.sniff(data) {
+				return imp, nil
+			}
+		}
+	}
+	for _, imp := range importers {
+		if imp.sniff(data) {
+			return imp, nil
+		}
+	}
+	return importer{}, fmt.Errorf("%s: unrecognized palette format (use --format: %s)", path, FormatList(ImportFormats()))
+}
+
+// FormatList joins format names for usage text and errors
+func FormatList(formats []Format) string {
+	names := make([]string, len(formats))
+	for i, f := range formats {
+		names[i] = string(f)
+	}
+	return strings.Join(names, ", ")
+}
+
+// ansiNames are the conventional names of the 16 terminal colors
+var ansiNames = []string{
+	"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+	"bright black", "bright red", "bright green", "bright yellow",
+	"bright blue", "bright magenta", "bright cyan", "bright white",
+}
+
+// complete lists the colors a theme is missing, in terminal terms
+func complete(g *scheme.Gogh) error {
+	var missing []string
+	if g.Background == "" {
+		missing = append(missing, "background")
+	}
+	if g.Foreground == "" {
+		missing = append(missing, "foreground")
+	}
+	for i, p := range g.ANSI() {
+		if *p == "" {
+			missing = append(missing, fmt.Sprintf("color%d (%s)", i, ansiNames[i]))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// variantOf guesses dark or light from the background's lightness
+func variantOf(bg string) string {
+	c, err := colorful.Hex("#" + strings.TrimPrefix(bg, "#"))
+	if err != nil {
+		return "dark"
+	}
+	if l, _, _ := c.OkLch(); l > 0.6 {
+		return "light"
+	}
+	return "dark"
+}
+
+// hexColor normalizes the color spellings these formats use (#rrggbb,
+// 0xrrggbb, X11 rgb:r/g/b) to what scheme.ParseColor accepts
+func hexColor(v string) string {
+	v = strings.Trim(strings.TrimSpace(v), `"'`)
+	switch {
+	case strings.HasPrefix(v, "0x") || strings.HasPrefix(v, "0X"):
+		return "#" + v[2:]
+	case strings.HasPrefix(v, "rgb:"):
+		parts := strings.Split(v[4:], "/")
+		if len(parts) != 3 {
+			return v
+		}
+		out := "#"
+		for _, p := range parts {
+			n, err := strconv.ParseUint(p, 16, 16)
+			if err != nil || len(p) == 0 || len(p) > 4 {
+				return v
+			}
+			// Scale 1–4 hex digits to 8 bits
+			full := math.Pow(16, float64(len(p))) - 1
+			out += fmt.Sprintf("%02x", int(math.Round(float64(n)/full*255)))
+		}
+		return out
+	}
+	return v
+}
+
+// hasAny reports whether data contains any of the markers
+func hasAny(data []byte, markers ...string) bool {
+	for _, m := range markers {
+		if bytes.Contains(data, []byte(m)) {
+			return true
+		}
+	}
+	return false
+}
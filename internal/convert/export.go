@@ -0,0 +1,71 @@
+package convert
+
+import (
+	"fmt"
+
+	"github.com/jaycee1285/base16changer/internal/scheme"
+)
+
+// exporter renders a scheme in one format
+type exporter struct {
+	format Format
+	ext    string // conventional file extension, with dot
+	render func(s *scheme.Base16) ([]byte, error)
+}
+
+var exporters = []exporter{
+	{FormatGogh, ".yml", exportGogh},
+	{FormatITerm2, ".itermcolors", exportITerm2},
+	{FormatWindowsTerminal, ".json", exportWindowsTerminal},
+	{FormatAlacritty, ".toml", exportAlacritty},
+	{FormatXresources, ".Xresources", exportXresources},
+	{FormatPywal, ".json", exportPywal},
+	{FormatCSS, ".css", exportCSS},
+	{FormatSCSS, ".scss", exportSCSS},
+	{FormatGPL, ".gpl", exportGPL},
+	{FormatTokens, ".tokens.json", exportTokens},
+	{FormatNix, ".nix", exportNix},
+}
+
+// ExportFormats lists the formats Export writes
+func ExportFormats() []Format {
+	out := make([]Format, len(exporters))
+	for i, exp := range exporters {
+		out[i] = exp.format
+	}
+	return out
+}
+
+// ParseExportFormat checks a format name against the exportable formats
+func ParseExportFormat(name string) (Format, error) {
+	for _, f := range ExportFormats() {
+		if string(f) == name {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown export format %q (expected one of: %s)", name, FormatList(ExportFormats()))
+}
+
+// Export renders s in a foreign palette format. Terminal formats carry the
+// 16 ANSI colors (mapped like the kitty template), background, foreground
+// and cursor, so importing the output gives back those colors exactly;
+// the remaining base16 slots are derived again on import. Design-token
+// formats carry every slot by name.
+func Export(s *scheme.Base16, format Format) ([]byte, error) {
+	for _, exp := range exporters {
+		if exp.format == format {
+			return exp.render(s)
+		}
+	}
+	return nil, fmt.Errorf("unknown export format %q (expected one of: %s)", format, FormatList(ExportFormats()))
+}
+
+// Extension returns the conventional file extension of an export format
+func Extension(format Format) string {
+	for _, exp := range exporters {
+		if exp.format == format {
+			return exp.ext
+		}
+	}
+	return ""
+}
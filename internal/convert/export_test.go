@@ -0,0 +1,122 @@
+package convert
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jaycee1285/base16changer/internal/scheme"
+)
+
+const dracula = `system: "base24"
+name: "Dracula"
+author: "Mike Barkmin"
+variant: "dark"
+palette:
+  base00: "#282a36"
+  base01: "#363447"
+  base02: "#44475a"
+  base03: "#6272a4"
+  base04: "#9ea8c7"
+  base05: "#f8f8f2"
+  base06: "#f0f1f4"
+  base07: "#ffffff"
+  base08: "#ff5555"
+  base09: "#ffb86c"
+  base0A: "#f1fa8c"
+  base0B: "#50fa7b"
+  base0C: "#8be9fd"
+  base0D: "#80bfff"
+  base0E: "#ff79c6"
+  base0F: "#bd93f9"
+  base10: "#1e2029"
+  base11: "#16171d"
+  base12: "#f28c8c"
+  base13: "#eef5a3"
+  base14: "#a3f5b8"
+  base15: "#baedf7"
+  base16: "#a3ccf5"
+  base17: "#f5a3d2"
+`
+
+const solarizedLight = `system: "base16"
+name: "Solarized Light"
+author: "Ethan Schoonover"
+variant: "light"
+palette:
+  base00: "#fdf6e3"
+  base01: "#eee8d5"
+  base02: "#93a1a1"
+  base03: "#839496"
+  base04: "#657b83"
+  base05: "#586e75"
+  base06: "#073642"
+  base07: "#002b36"
+  base08: "#dc322f"
+  base09: "#cb4b16"
+  base0A: "#b58900"
+  base0B: "#859900"
+  base0C: "#2aa198"
+  base0D: "#268bd2"
+  base0E: "#6c71c4"
+  base0F: "#d33682"
+`
+
+// TestExportRoundTrip checks that importing an export gives back the 16
+// ANSI colors, background, foreground and cursor it was written with
+func TestExportRoundTrip(t *testing.T) {
+	schemes := map[string]string{"dracula": dracula, "solarized-light": solarizedLight}
+	formats := []Format{FormatGogh, FormatITerm2, FormatWindowsTerminal, FormatAlacritty, FormatXresources, FormatPywal}
+
+	for name, src := range schemes {
+		dir := t.TempDir()
+		path := filepath.Join(dir, name+".yaml")
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		s, err := scheme.Parse(path)
+		if err != nil {
+			t.Fatalf("parse %s: %v", name, err)
+		}
+		want := s.ToGogh()
+
+		for _, f := range formats {
+			t.Run(name+"/"+string(f), func(t *testing.T) {
+				data, err := Export(s, f)
+				if err != nil {
+					t.Fatalf("export: %v", err)
+				}
+				out := filepath.Join(t.TempDir(), name+Extension(f))
+				if err := os.WriteFile(out, data, 0o644); err != nil {
+					t.Fatal(err)
+				}
+
+				for _, format := range []Format{f, ""} {
+					imported, err := Import(out, format)
+					if err != nil {
+						t.Fatalf("import (format %q): %v", format, err)
+					}
+					if len(imported) != 1 {
+						t.Fatalf("import (format %q): got %d schemes, want 1", format, len(imported))
+					}
+					got := imported[0].ToGogh()
+					wantANSI, gotANSI := want.ANSI(), got.ANSI()
+					for i := range wantANSI {
+						if *gotANSI[i] != *wantANSI[i] {
+							t.Errorf("format %q: %s = %s, want %s", format, ansiNames[i], *gotANSI[i], *wantANSI[i])
+						}
+					}
+					for _, c := range []struct{ name, got, want string }{
+						{"background", got.Background, want.Background},
+						{"foreground", got.Foreground, want.Foreground},
+						{"cursor", got.Cursor, want.Cursor},
+					} {
+						if c.got != c.want {
+							t.Errorf("format %q: %s = %s, want %s", format, c.name, c.got, c.want)
+						}
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package convert
+
+import (
+	"gopkg.in/yaml.v3"
+
+	"github.com/jaycee1285/base16changer/internal/scheme"
+)
+
+func sniffGogh(data []byte) bool {
+	return hasAny(data, "color_01:")
+}
+
+// parseGogh reads a Gogh YAML theme; scheme.Parse reads these too, this
+// lets import and export round-trip them
+func parseGogh(data []byte) ([]*scheme.Gogh, error) {
+	var g scheme.Gogh
+	if err := yaml.Unmarshal(data, &g); err != nil {
+		return nil, err
+	}
+	return []*scheme.Gogh{&g}, nil
+}
+
+// exportGogh writes a Gogh YAML theme
+func exportGogh(s *scheme.Base16) ([]byte, error) {
+	return yaml.Marshal(s.ToGogh())
+}
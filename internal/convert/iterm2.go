@@ -0,0 +1,117 @@
+package convert
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/lucasb-eyer/go-colorful"
+
+	"github.com/jaycee1285/base16changer/internal/scheme"
+)
+
+// plistNode is any element of an XML property list, children in order
+type plistNode struct {
+	XMLName xml.Name
+	Text    string      `xml:",chardata"`
+	Nodes   []plistNode `xml:",any"`
+}
+
+// dict returns a <dict>'s entries keyed by <key> text
+func (n plistNode) dict() map[string]plistNode {
+	out := map[string]plistNode{}
+	for i := 0; i+1 < len(n.Nodes); i += 2 {
+		if n.Nodes[i].XMLName.Local == "key" {
+			out[strings.TrimSpace(n.Nodes[i].Text)] = n.Nodes[i+1]
+		}
+	}
+	return out
+}
+
+func sniffITerm2(data []byte) bool {
+	return hasAny(data, "<plist") && hasAny(data, "Ansi 0 Color")
+}
+
+// parseITerm2 reads an .itermcolors plist: a dict of "Ansi N Color",
+// "Background Color" … entries, each a dict of 0–1 color components.
+// Components are taken as sRGB whatever "Color Space" says.
+func parseITerm2(data []byte) ([]*scheme.Gogh, error) {
+	var root plistNode
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("plist: %w", err)
+	}
+	if len(root.Nodes) == 0 || root.Nodes[0].XMLName.Local != "dict" {
+		return nil, fmt.Errorf("plist: expected a top-level <dict>")
+	}
+	entries := root.Nodes[0].dict()
+
+	color := func(key string) (string, error) {
+		entry, ok := entries[key]
+		if !ok {
+			return "", nil
+		}
+		comps := entry.dict()
+		hex := "#"
+		for _, c := range []string{"Red Component", "Green Component", "Blue Component"} {
+			v, err := strconv.ParseFloat(strings.TrimSpace(comps[c].Text), 64)
+			if err != nil {
+				return "", fmt.Errorf("%s: bad %s %q", key, strings.ToLower(c), comps[c].Text)
+			}
+			hex += fmt.Sprintf("%02x", int(math.Round(math.Max(0, math.Min(1, v))*255)))
+		}
+		return hex, nil
+	}
+
+	g := &scheme.Gogh{}
+	var err error
+	for i, p := range g.ANSI() {
+		if *p, err = color(fmt.Sprintf("Ansi %d Color", i)); err != nil {
+			return nil, err
+		}
+	}
+	for key, p := range map[string]*string{
+		"Background Color": &g.Background,
+		"Foreground Color": &g.Foreground,
+		"Cursor Color":     &g.Cursor,
+	} {
+		if *p, err = color(key); err != nil {
+			return nil, err
+		}
+	}
+	return []*scheme.Gogh{g}, nil
+}
+
+// exportITerm2 writes an .itermcolors plist
+func exportITerm2(s *scheme.Base16) ([]byte, error) {
+	g := s.ToGogh()
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+`)
+	entry := func(key, hex string) {
+		c, _ := colorful.Hex(hex)
+		fmt.Fprintf(&b, "\t<key>%s</key>\n\t<dict>\n", key)
+		fmt.Fprintf(&b, "\t\t<key>Alpha Component</key>\n\t\t<real>1</real>\n")
+		fmt.Fprintf(&b, "\t\t<key>Blue Component</key>\n\t\t<real>%.8f</real>\n", c.B)
+		fmt.Fprintf(&b, "\t\t<key>Color Space</key>\n\t\t<string>sRGB</string>\n")
+		fmt.Fprintf(&b, "\t\t<key>Green Component</key>\n\t\t<real>%.8f</real>\n", c.G)
+		fmt.Fprintf(&b, "\t\t<key>Red Component</key>\n\t\t<real>%.8f</real>\n", c.R)
+		b.WriteString("\t</dict>\n")
+	}
+	for i, p := range g.ANSI() {
+		entry(fmt.Sprintf("Ansi %d Color", i), *p)
+	}
+	entry("Background Color", g.Background)
+	entry("Bold Color", g.Foreground)
+	entry("Cursor Color", g.Cursor)
+	entry("Cursor Text Color", g.Background)
+	entry("Foreground Color", g.Foreground)
+	entry("Selected Text Color", s.Palette.Hex("base05"))
+	entry("Selection Color", s.Palette.Hex("base02"))
+	b.WriteString("</dict>\n</plist>\n")
+	return []byte(b.String()), nil
+}
@@ -0,0 +1,72 @@
+package convert
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jaycee1285/base16changer/internal/scheme"
+)
+
+var (
+	// kittyMeta matches the "## name: …" headers of kitty-themes files
+	kittyMeta = regexp.MustCompile(`^##\s*(name|author)\s*:\s*(.+)$`)
+	// kittyColor matches a color0 or background line
+	kittyColor = regexp.MustCompile(`(?m)^\s*(color0|background)\s+#?[0-9a-fA-F]{3,6}\s*$`)
+)
+
+func sniffKitty(data []byte) bool {
+	return kittyColor.Match(data)
+}
+
+// parseKitty reads a kitty.conf theme: "background #rrggbb",
+// "color0 #rrggbb" … lines, plus the kitty-themes name and author headers
+func parseKitty(data []byte) ([]*scheme.Gogh, error) {
+	g := &scheme.Gogh{}
+	ansi := g.ANSI()
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if m := kittyMeta.FindStringSubmatch(line); m != nil {
+			if m[1] == "name" {
+				g.Name = strings.TrimSpace(m[2])
+			} else {
+				g.Author = strings.TrimSpace(m[2])
+			}
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		key, value := fields[0], hexColor(fields[1])
+		switch key {
+		case "background":
+			g.Background = value
+		case "foreground":
+			g.Foreground = value
+		case "cursor":
+			g.Cursor = value
+		default:
+			if n, ok := strings.CutPrefix(key, "color"); ok {
+				if i, err := strconv.Atoi(n); err == nil && i < len(ansi) {
+					*ansi[i] = value
+				}
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if g.Background == "" && g.Color01 == "" {
+		return nil, nil
+	}
+	return []*scheme.Gogh{g}, nil
+}
@@ -0,0 +1,87 @@
+package convert
+
+import (
+	"encoding/json"
+
+	"github.com/jaycee1285/base16changer/internal/scheme"
+)
+
+// pywalTheme is pywal's colors.json cache file
+type pywalTheme struct {
+	Wallpaper string `json:"wallpaper"`
+	Alpha     string `json:"alpha"`
+	Special   struct {
+		Background string `json:"background"`
+		Foreground string `json:"foreground"`
+		Cursor     string `json:"cursor"`
+	} `json:"special"`
+	Colors pywalColors `json:"colors"`
+}
+
+// pywalColors keeps color0 … color15 in order when encoded
+type pywalColors struct {
+	Color0  string `json:"color0"`
+	Color1  string `json:"color1"`
+	Color2  string `json:"color2"`
+	Color3  string `json:"color3"`
+	Color4  string `json:"color4"`
+	Color5  string `json:"color5"`
+	Color6  string `json:"color6"`
+	Color7  string `json:"color7"`
+	Color8  string `json:"color8"`
+	Color9  string `json:"color9"`
+	Color10 string `json:"color10"`
+	Color11 string `json:"color11"`
+	Color12 string `json:"color12"`
+	Color13 string `json:"color13"`
+	Color14 string `json:"color14"`
+	Color15 string `json:"color15"`
+}
+
+func (c *pywalColors) ansi() []*string {
+	return []*string{
+		&c.Color0, &c.Color1, &c.Color2, &c.Color3, &c.Color4, &c.Color5, &c.Color6, &c.Color7,
+		&c.Color8, &c.Color9, &c.Color10, &c.Color11, &c.Color12, &c.Color13, &c.Color14, &c.Color15,
+	}
+}
+
+func sniffPywal(data []byte) bool {
+	return hasAny(data, `"special"`) && hasAny(data, `"color0"`)
+}
+
+// parsePywal reads a pywal colors.json
+func parsePywal(data []byte) ([]*scheme.Gogh, error) {
+	var p pywalTheme
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+	g := &scheme.Gogh{
+		Background: p.Special.Background,
+		Foreground: p.Special.Foreground,
+		Cursor:     p.Special.Cursor,
+	}
+	ansi := g.ANSI()
+	for i, c := range p.Colors.ansi() {
+		*ansi[i] = *c
+	}
+	return []*scheme.Gogh{g}, nil
+}
+
+// exportPywal writes a colors.json in pywal's format, for tools that read
+// ~/.cache/wal/colors.json
+func exportPywal(s *scheme.Base16) ([]byte, error) {
+	g := s.ToGogh()
+	p := pywalTheme{Alpha: "100"}
+	p.Special.Background = g.Background
+	p.Special.Foreground = g.Foreground
+	p.Special.Cursor = g.Cursor
+	ansi := g.ANSI()
+	for i, c := range p.Colors.ansi() {
+		*c = *ansi[i]
+	}
+	data, err := json.MarshalIndent(p, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,127 @@
+package convert
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jaycee1285/base16changer/internal/scheme"
+)
+
+// Design-token formats carry the palette slots by name rather than as
+// ANSI colors. They are rendered from the builder variables of ToMap, so
+// they hold exactly what templates see.
+const (
+	FormatCSS    Format = "css"    // :root { --base00: … } custom properties
+	FormatSCSS   Format = "scss"   // $base00 variables and a $base16 map
+	FormatGPL    Format = "gpl"    // GIMP / Inkscape palette
+	FormatTokens Format = "tokens" // W3C design tokens JSON
+	FormatNix    Format = "nix"    // attrset for Stylix base16Scheme
+)
+
+// TokenFormats lists the design-token export formats
+func TokenFormats() []Format {
+	return []Format{FormatCSS, FormatSCSS, FormatGPL, FormatTokens, FormatNix}
+}
+
+// ParseTokenFormat checks a format name against the design-token formats
+func ParseTokenFormat(name string) (Format, error) {
+	for _, f := range TokenFormats() {
+		if string(f) == name {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown token format %q (expected one of: %s)", name, FormatList(TokenFormats()))
+}
+
+// tokenHeader describes the scheme in one line for file comments
+func tokenHeader(m map[string]string) string {
+	h := m["scheme-name"]
+	if m["scheme-author"] != "" {
+		h += " by " + m["scheme-author"]
+	}
+	return h + ", generated by base16changer"
+}
+
+func exportCSS(s *scheme.Base16) ([]byte, error) {
+	m := s.ToMap()
+	var b strings.Builder
+	fmt.Fprintf(&b, "/* %s */\n:root {\n", strings.ReplaceAll(tokenHeader(m), "*/", "* /"))
+	for _, name := range s.Slots() {
+		fmt.Fprintf(&b, "  --%s: #%s;\n", name, m[name+"-hex"])
+	}
+	b.WriteString("}\n")
+	return []byte(b.String()), nil
+}
+
+func exportSCSS(s *scheme.Base16) ([]byte, error) {
+	m := s.ToMap()
+	slots := s.Slots()
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s\n\n", tokenHeader(m))
+	for _, name := range slots {
+		fmt.Fprintf(&b, "$%s: #%s;\n", name, m[name+"-hex"])
+	}
+	fmt.Fprintf(&b, "\n$%s: (\n", s.SystemName())
+	for _, name := range slots {
+		fmt.Fprintf(&b, "  \"%s\": $%s,\n", name, name)
+	}
+	b.WriteString(");\n")
+	return []byte(b.String()), nil
+}
+
+// exportGPL writes a GIMP palette, which Inkscape and Krita read too
+func exportGPL(s *scheme.Base16) ([]byte, error) {
+	m := s.ToMap()
+	var b strings.Builder
+	fmt.Fprintf(&b, "GIMP Palette\nName: %s\nColumns: 8\n# %s\n", m["scheme-name"], tokenHeader(m))
+	for _, name := range s.Slots() {
+		fmt.Fprintf(&b, "%3s %3s %3s\t%s\n", m[name+"-rgb-r"], m[name+"-rgb-g"], m[name+"-rgb-b"], name)
+	}
+	return []byte(b.String()), nil
+}
+
+// designToken is one color in the W3C design tokens format
+type designToken struct {
+	Type  string `json:"$type"`
+	Value string `json:"$value"`
+}
+
+// exportTokens writes W3C design tokens: a group named after the system
+// (base16 or base24) with one color token per slot
+func exportTokens(s *scheme.Base16) ([]byte, error) {
+	m := s.ToMap()
+	group := map[string]any{"$description": tokenHeader(m)}
+	for _, name := range s.Slots() {
+		group[name] = designToken{Type: "color", Value: "#" + m[name+"-hex"]}
+	}
+	data, err := json.MarshalIndent(map[string]any{s.SystemName(): group}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// exportNix writes an attrset in the shape Stylix and base16.nix accept
+// for base16Scheme: metadata plus bare hex colors
+func exportNix(s *scheme.Base16) ([]byte, error) {
+	m := s.ToMap()
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n{\n", tokenHeader(m))
+	fmt.Fprintf(&b, "  system = %s;\n", nixString(m["scheme-system"]))
+	fmt.Fprintf(&b, "  name = %s;\n", nixString(m["scheme-name"]))
+	fmt.Fprintf(&b, "  author = %s;\n", nixString(m["scheme-author"]))
+	fmt.Fprintf(&b, "  slug = %s;\n", nixString(m["scheme-slug"]))
+	fmt.Fprintf(&b, "  variant = %s;\n", nixString(m["scheme-variant"]))
+	for _, name := range s.Slots() {
+		fmt.Fprintf(&b, "  %s = \"%s\";\n", name, m[name+"-hex"])
+	}
+	b.WriteString("}\n")
+	return []byte(b.String()), nil
+}
+
+// nixString quotes s as a Nix string literal
+func nixString(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "${", `\${`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
@@ -0,0 +1,159 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jaycee1285/base16changer/internal/scheme"
+)
+
+// wtScheme is one entry of Windows Terminal's "schemes" list
+type wtScheme struct {
+	Name                string `json:"name"`
+	Background          string `json:"background"`
+	Foreground          string `json:"foreground"`
+	CursorColor         string `json:"cursorColor,omitempty"`
+	SelectionBackground string `json:"selectionBackground,omitempty"`
+
+	Black        string `json:"black"`
+	Red          string `json:"red"`
+	Green        string `json:"green"`
+	Yellow       string `json:"yellow"`
+	Blue         string `json:"blue"`
+	Purple       string `json:"purple"`
+	Cyan         string `json:"cyan"`
+	White        string `json:"white"`
+	BrightBlack  string `json:"brightBlack"`
+	BrightRed    string `json:"brightRed"`
+	BrightGreen  string `json:"brightGreen"`
+	BrightYellow string `json:"brightYellow"`
+	BrightBlue   string `json:"brightBlue"`
+	BrightPurple string `json:"brightPurple"`
+	BrightCyan   string `json:"brightCyan"`
+	BrightWhite  string `json:"brightWhite"`
+}
+
+// ansi returns the scheme's 16 colors in terminal order
+func (w *wtScheme) ansi() []*string {
+	return []*string{
+		&w.Black, &w.Red, &w.Green, &w.Yellow, &w.Blue, &w.Purple, &w.Cyan, &w.White,
+		&w.BrightBlack, &w.BrightRed, &w.BrightGreen, &w.BrightYellow,
+		&w.BrightBlue, &w.BrightPurple, &w.BrightCyan, &w.BrightWhite,
+	}
+}
+
+func sniffWindowsTerminal(data []byte) bool {
+	return hasAny(data, `"brightBlack"`, `"schemes"`)
+}
+
+// parseWindowsTerminal reads a single scheme object, a list of them, or
+// every scheme in a settings.json, which is JSON with comments
+func parseWindowsTerminal(data []byte) ([]*scheme.Gogh, error) {
+	data = stripJSONC(data)
+	var schemes []wtScheme
+	var settings struct {
+		Schemes []wtScheme `json:"schemes"`
+	}
+	var one wtScheme
+	switch {
+	case json.Unmarshal(data, &schemes) == nil:
+	case json.Unmarshal(data, &settings) == nil && len(settings.Schemes) > 0:
+		schemes = settings.Schemes
+	default:
+		if err := json.Unmarshal(data, &one); err != nil {
+			return nil, fmt.Errorf("json: %w", err)
+		}
+		if one.Background == "" && one.Black == "" {
+			return nil, nil
+		}
+		schemes = []wtScheme{one}
+	}
+
+	var out []*scheme.Gogh
+	for _, w := range schemes {
+		g := &scheme.Gogh{
+			Name:       w.Name,
+			Background: w.Background,
+			Foreground: w.Foreground,
+			Cursor:     w.CursorColor,
+		}
+		ansi := g.ANSI()
+		for i, c := range w.ansi() {
+			*ansi[i] = *c
+		}
+		out = append(out, g)
+	}
+	return out, nil
+}
+
+// exportWindowsTerminal writes one scheme object for the "schemes" list
+// of settings.json
+func exportWindowsTerminal(s *scheme.Base16) ([]byte, error) {
+	g := s.ToGogh()
+	w := wtScheme{
+		Name:                s.Name,
+		Background:          g.Background,
+		Foreground:          g.Foreground,
+		CursorColor:         g.Cursor,
+		SelectionBackground: s.Palette.Hex("base02"),
+	}
+	ansi := g.ANSI()
+	for i, c := range w.ansi() {
+		*c = *ansi[i]
+	}
+	data, err := json.MarshalIndent(w, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// stripJSONC removes // and /* */ comments and trailing commas before a
+// closing bracket, which Windows Terminal allows in settings.json. String
+// contents are left alone.
+func stripJSONC(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	comma := -1 // index in out of a comma that may be trailing
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == '"':
+			start := i
+			for i++; i < len(data) && data[i] != '"'; i++ {
+				if data[i] == '\\' {
+					i++
+				}
+			}
+			out = append(out, data[start:min(i+1, len(data))]...)
+			comma = -1
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			i--
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				i = len(data)
+			} else {
+				i += end + 3
+			}
+		case c == ',':
+			comma = len(out)
+			out = append(out, c)
+		case c == '}' || c == ']':
+			if comma >= 0 {
+				out = append(out[:comma], out[comma+1:]...)
+			}
+			comma = -1
+			out = append(out, c)
+		default:
+			if c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+				comma = -1
+			}
+			out = append(out, c)
+		}
+	}
+	return out
+}
@@ -0,0 +1,88 @@
+package convert
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jaycee1285/base16changer/internal/scheme"
+)
+
+// xresourceLine matches "*.color0: value", "URxvt*background: value" …
+var xresourceLine = regexp.MustCompile(`^[\w.*-]*?[.*]?(color\d+|background|foreground|cursorColor)\s*:\s*(.+)$`)
+
+func sniffXresources(data []byte) bool {
+	return hasAny(data, "*.color0:", "*color0:", ".color0:", "*.background:", "*background:")
+}
+
+// parseXresources reads terminal colors from X resources, expanding
+// #define macros as the base16-xresources templates use them. Comments
+// start with !.
+func parseXresources(data []byte) ([]*scheme.Gogh, error) {
+	defines := map[string]string{}
+	g := &scheme.Gogh{}
+	ansi := g.ANSI()
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "!") {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(line, "#define"); ok {
+			if f := strings.Fields(rest); len(f) == 2 {
+				defines[f[0]] = f[1]
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			continue // other preprocessor directives
+		}
+
+		m := xresourceLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		value := strings.TrimSpace(m[2])
+		if v, ok := defines[value]; ok {
+			value = v
+		}
+		value = hexColor(value)
+
+		switch key := m[1]; key {
+		case "background":
+			g.Background = value
+		case "foreground":
+			g.Foreground = value
+		case "cursorColor":
+			g.Cursor = value
+		default:
+			if n, err := strconv.Atoi(strings.TrimPrefix(key, "color")); err == nil && n < len(ansi) {
+				*ansi[n] = value
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if g.Background == "" && g.Color01 == "" {
+		return nil, nil
+	}
+	return []*scheme.Gogh{g}, nil
+}
+
+// exportXresources writes *.foreground, *.background, *.cursorColor and
+// *.color0 … *.color15 resources
+func exportXresources(s *scheme.Base16) ([]byte, error) {
+	g := s.ToGogh()
+	var b strings.Builder
+	fmt.Fprintf(&b, "! %s\n! Generated by base16changer\n\n", s.Name)
+	fmt.Fprintf(&b, "*.foreground:  %s\n*.background:  %s\n*.cursorColor: %s\n\n", g.Foreground, g.Background, g.Cursor)
+	for i, p := range g.ANSI() {
+		fmt.Fprintf(&b, "*.color%d: %s\n", i, *p)
+	}
+	return []byte(b.String()), nil
+}
@@ -0,0 +1,238 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ANSI colors used when rendering colored diffs
+const (
+	colorReset = "\x1b[0m"
+	colorBold  = "\x1b[1m"
+	colorRed   = "\x1b[31m"
+	colorGreen = "\x1b[32m"
+	colorCyan  = "\x1b[36m"
+)
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+type op struct {
+	kind opKind
+	line string
+}
+
+// Unified returns a unified diff from oldText to newText with the given
+// number of context lines, or "" when they are identical. Set color to
+// wrap headers and changed lines in ANSI escapes.
+func Unified(oldName, newName, oldText, newText string, context int, color bool) string {
+	if oldText == newText {
+		return ""
+	}
+
+	ops := lineDiff(splitLines(oldText), splitLines(newText))
+
+	var b strings.Builder
+	paint := func(c, s string) string {
+		if !color {
+			return s
+		}
+		return c + s + colorReset
+	}
+	b.WriteString(paint(colorBold, "--- "+oldName) + "\n")
+	b.WriteString(paint(colorBold, "+++ "+newName) + "\n")
+
+	for _, h := range hunks(ops, context) {
+		b.WriteString(paint(colorCyan, fmt.Sprintf("@@ -%s +%s @@", h.oldRange(), h.newRange())) + "\n")
+		for _, o := range ops[h.start:h.end] {
+			line, marker := strings.CutSuffix(o.line, noNewline)
+			switch o.kind {
+			case opEqual:
+				b.WriteString(" " + line + "\n")
+			case opDelete:
+				b.WriteString(paint(colorRed, "-"+line) + "\n")
+			case opInsert:
+				b.WriteString(paint(colorGreen, "+"+line) + "\n")
+			}
+			if marker {
+				b.WriteString(noNewline[1:] + "\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+// noNewline is appended to a final line that lacks its newline, so it
+// differs from the same line with one and prints the marker diff -u uses
+const noNewline = "\n\\ No newline at end of file"
+
+// splitLines splits text into lines; a trailing newline does not add an
+// empty final line, and a missing one is marked with noNewline
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	if !strings.HasSuffix(s, "\n") {
+		lines[len(lines)-1] += noNewline
+	}
+	return lines
+}
+
+// lineDiff computes a shortest edit script with Myers' O(ND) algorithm
+func lineDiff(a, b []string) []op {
+	n, m := len(a), len(b)
+	max := n + m
+	offset := max + 1
+	v := make([]int, 2*max+2)
+	var trace [][]int
+
+	for d := 0; d <= max; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1] // move down (insert)
+			} else {
+				x = v[offset+k-1] + 1 // move right (delete)
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, a, b, d, offset)
+			}
+		}
+	}
+	return nil
+}
+
+// backtrack walks the Myers trace from the end to recover the edit script
+func backtrack(trace [][]int, a, b []string, d, offset int) []op {
+	x, y := len(a), len(b)
+	var ops []op
+	for ; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			ops = append(ops, op{opEqual, a[x]})
+		}
+		if x == prevX {
+			y--
+			ops = append(ops, op{opInsert, b[y]})
+		} else {
+			x--
+			ops = append(ops, op{opDelete, a[x]})
+		}
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		ops = append(ops, op{opEqual, a[x]})
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
+
+// hunk is a slice of ops plus the line numbers it starts at
+type hunk struct {
+	start, end       int // ops[start:end]
+	oldLine, newLine int // 1-based first line in each file
+	oldLen, newLen   int
+}
+
+func (h hunk) oldRange() string { return formatRange(h.oldLine, h.oldLen) }
+func (h hunk) newRange() string { return formatRange(h.newLine, h.newLen) }
+
+func formatRange(line, n int) string {
+	if n == 0 {
+		line-- // empty ranges point at the line before
+	}
+	if n == 1 {
+		return fmt.Sprintf("%d", line)
+	}
+	return fmt.Sprintf("%d,%d", line, n)
+}
+
+// hunks groups changes that are within 2*context lines of each other
+func hunks(ops []op, context int) []hunk {
+	var out []hunk
+	oldLine, newLine := 1, 1
+	i := 0
+	for i < len(ops) {
+		if ops[i].kind == opEqual {
+			oldLine++
+			newLine++
+			i++
+			continue
+		}
+
+		// Start a hunk with up to context equal lines before the change
+		start := i
+		for start > 0 && i-start < context && ops[start-1].kind == opEqual {
+			start--
+		}
+		h := hunk{start: start, oldLine: oldLine - (i - start), newLine: newLine - (i - start)}
+
+		// Extend while the next change is close enough to merge
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != opEqual {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == opEqual {
+				run++
+			}
+			if run == len(ops) || run-end > 2*context {
+				end += min(context, run-end)
+				break
+			}
+			end = run
+		}
+		h.end = end
+
+		for _, o := range ops[h.start:h.end] {
+			if o.kind != opInsert {
+				h.oldLen++
+			}
+			if o.kind != opDelete {
+				h.newLen++
+			}
+		}
+		out = append(out, h)
+
+		for _, o := range ops[i:end] {
+			if o.kind != opInsert {
+				oldLine++
+			}
+			if o.kind != opDelete {
+				newLine++
+			}
+		}
+		i = end
+	}
+	return out
+}
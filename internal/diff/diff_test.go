@@ -0,0 +1,66 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// numbered returns the lines 1…n, with some replaced
+func numbered(n int, replace map[int]string) string {
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		if s, ok := replace[i]; ok {
+			b.WriteString(s + "\n")
+		} else {
+			fmt.Fprintf(&b, "%d\n", i)
+		}
+	}
+	return b.String()
+}
+
+// The expected hunks match diff -u (-U2 for the context cases)
+func TestUnified(t *testing.T) {
+	cases := []struct {
+		name, old, new string
+		context        int
+		want           string
+	}{
+		{"identical", "a\n", "a\n", 3, ""},
+		{"empty to content", "", "a\nb\nc\n", 3,
+			"@@ -0,0 +1,3 @@\n+a\n+b\n+c\n"},
+		{"content to empty", "a\nb\nc\n", "", 3,
+			"@@ -1,3 +0,0 @@\n-a\n-b\n-c\n"},
+		{"newline added", "a\nb", "a\nb\n", 3,
+			"@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n"},
+		{"newline removed", "a\nb\n", "a\nb", 3,
+			"@@ -1,2 +1,2 @@\n a\n-b\n+b\n\\ No newline at end of file\n"},
+		{"no newline on either side", "a\nb", "a\nc", 3,
+			"@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n"},
+		{"adjacent hunks merge", numbered(12, nil), numbered(12, map[int]string{3: "x", 8: "y"}), 2,
+			"@@ -1,10 +1,10 @@\n 1\n 2\n-3\n+x\n 4\n 5\n 6\n 7\n-8\n+y\n 9\n 10\n"},
+		{"distant hunks stay apart", numbered(12, nil), numbered(12, map[int]string{3: "x", 9: "y"}), 2,
+			"@@ -1,5 +1,5 @@\n 1\n 2\n-3\n+x\n 4\n 5\n@@ -7,5 +7,5 @@\n 7\n 8\n-9\n+y\n 10\n 11\n"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := Unified("old", "new", c.old, c.new, c.context, false)
+			want := c.want
+			if want != "" {
+				want = "--- old\n+++ new\n" + want
+			}
+			if got != want {
+				t.Errorf("got\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestUnifiedColor(t *testing.T) {
+	got := Unified("old", "new", "a\n", "b\n", 3, true)
+	for _, want := range []string{colorBold + "--- old" + colorReset, colorRed + "-a" + colorReset, colorGreen + "+b" + colorReset} {
+		if !strings.Contains(got, want) {
+			t.Errorf("%q does not contain %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package palette
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+	"math/rand/v2"
+	"os"
+	"sort"
+
+	"github.com/lucasb-eyer/go-colorful"
+	_ "golang.org/x/image/webp"
+)
+
+// maxSamples bounds how many pixels are clustered; larger images are
+// sampled on an even grid
+const maxSamples = 40000
+
+// Cluster is one dominant color of an image in OKLab, with the share of
+// sampled pixels it covers (0–1)
+type Cluster struct {
+	L, A, B float64
+	Weight  float64
+}
+
+// Chroma returns the cluster's OKLCH chroma
+func (c Cluster) Chroma() float64 { return math.Hypot(c.A, c.B) }
+
+// Hue returns the cluster's OKLCH hue in degrees (0–360)
+func (c Cluster) Hue() float64 {
+	h := math.Atan2(c.B, c.A) * 180 / math.Pi
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+// Extract decodes a jpg, png, gif or webp image and returns its k dominant
+// colors, found by k-means clustering in OKLab, heaviest first
+func Extract(path string, k int) ([]Cluster, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", path, err)
+	}
+	points := sample(img)
+	if len(points) == 0 {
+		return nil, fmt.Errorf("%s has no opaque pixels", path)
+	}
+	return kmeans(points, k), nil
+}
+
+// sample converts an even grid of opaque pixels to OKLab
+func sample(img image.Image) [][3]float64 {
+	bounds := img.Bounds()
+	step := int(math.Sqrt(float64(bounds.Dx()*bounds.Dy()) / maxSamples))
+	step = max(step, 1)
+
+	var points [][3]float64
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += step {
+		for x := bounds.Min.X; x < bounds.Max.X; x += step {
+			px := img.At(x, y)
+			if _, _, _, a := px.RGBA(); a < 0x8000 {
+				continue // mostly transparent
+			}
+			c, _ := colorful.MakeColor(px)
+			l, a, b := c.OkLab()
+			points = append(points, [3]float64{l, a, b})
+		}
+	}
+	return points
+}
+
+// kmeans clusters points with k-means++ seeding. The seed is fixed so the
+// same image always gives the same palette.
+func kmeans(points [][3]float64, k int) []Cluster {
+	k = min(k, len(points))
+	rng := rand.New(rand.NewPCG(16, 24))
+
+	centers := [][3]float64{points[rng.IntN(len(points))]}
+	dist := make([]float64, len(points))
+	for len(centers) < k {
+		var total float64
+		for i, p := range points {
+			dist[i] = sqDist(p, centers[nearest(p, centers)])
+			total += dist[i]
+		}
+		if total == 0 {
+			break // fewer distinct colors than k
+		}
+		r := rng.Float64() * total
+		i := 0
+		for ; i < len(points)-1 && r > dist[i]; i++ {
+			r -= dist[i]
+		}
+		centers = append(centers, points[i])
+	}
+
+	assign := make([]int, len(points))
+	for iter := 0; iter < 24; iter++ {
+		changed := iter == 0
+		for i, p := range points {
+			if c := nearest(p, centers); c != assign[i] {
+				assign[i], changed = c, true
+			}
+		}
+		if !changed {
+			break
+		}
+
+		sums := make([][4]float64, len(centers))
+		for i, p := range points {
+			s := &sums[assign[i]]
+			s[0], s[1], s[2], s[3] = s[0]+p[0], s[1]+p[1], s[2]+p[2], s[3]+1
+		}
+		for c, s := range sums {
+			if s[3] > 0 {
+				centers[c] = [3]float64{s[0] / s[3], s[1] / s[3], s[2] / s[3]}
+			}
+		}
+	}
+
+	counts := make([]int, len(centers))
+	for _, c := range assign {
+		counts[c]++
+	}
+	var clusters []Cluster
+	for c, p := range centers {
+		if counts[c] > 0 {
+			clusters = append(clusters, Cluster{L: p[0], A: p[1], B: p[2], Weight: float64(counts[c]) / float64(len(points))})
+		}
+	}
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].Weight > clusters[j].Weight })
+	return clusters
+}
+
+func nearest(p [3]float64, centers [][3]float64) int {
+	best, bestD := 0, math.Inf(1)
+	for i, c := range centers {
+		if d := sqDist(p, c); d < bestD {
+			best, bestD = i, d
+		}
+	}
+	return best
+}
+
+func sqDist(a, b [3]float64) float64 {
+	dl, da, db := a[0]-b[0], a[1]-b[1], a[2]-b[2]
+	return dl*dl + da*da + db*db
+}
@@ -0,0 +1,239 @@
+package palette
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/lucasb-eyer/go-colorful"
+
+	"github.com/jaycee1285/base16changer/internal/scheme"
+)
+
+// Strategies accepted by Generate
+const (
+	StrategyMaterial      = "material"      // Material 3 tonal palettes
+	StrategyAnalogous     = "analogous"     // accents pulled toward seed ±30°
+	StrategyTriadic       = "triadic"       // accents pulled toward seed, +120°, +240°
+	StrategyComplementary = "complementary" // accents pulled toward seed and +180°
+)
+
+// Strategies lists the generator strategies in help order
+var Strategies = []string{StrategyMaterial, StrategyAnalogous, StrategyTriadic, StrategyComplementary}
+
+// Options configures Generate
+type Options struct {
+	Name     string
+	Seeds    []string // hex colors; the first is the primary
+	Variant  string   // dark or light
+	Strategy string
+	Base24   bool // also fill base10–base17
+}
+
+// Tones (CIE L*, 0–100) of base00–base07, and of the accents
+var (
+	darkTones  = [8]float64{6, 12, 22, 45, 80, 94, 97, 99}
+	lightTones = [8]float64{98, 94, 87, 57, 30, 8, 5, 2}
+)
+
+// Standard hues (CIE LCh degrees) of the semantic accent slots
+var accentHues = []accent{
+	{"base08", 30},  // red
+	{"base09", 60},  // orange
+	{"base0A", 90},  // yellow
+	{"base0B", 135}, // green
+	{"base0C", 200}, // cyan
+	{"base0D", 270}, // blue
+	{"base0E", 320}, // purple
+}
+
+// Generate builds a complete base16 (or base24) scheme from seed colors.
+// Neutrals are tinted with the primary seed's hue. Material uses the
+// primary, secondary and tertiary tonal palettes for blue, cyan and purple
+// and harmonizes the other accents toward the primary. The other
+// strategies give each seed's hue to the accent slot nearest it and pull
+// the remaining accents toward the harmony's hues, keeping their roles
+// recognizable. Seeds only contribute hue and chroma: every accent sits at
+// the same tone so it stays readable on the background ramp.
+//
+// Tonal palettes approximate Material's HCT with CIE LCh: tone is L*.
+func Generate(opts Options) (*scheme.Base16, error) {
+	if len(opts.Seeds) == 0 {
+		return nil, fmt.Errorf("at least one seed color is required")
+	}
+	seeds := make([]colorful.Color, len(opts.Seeds))
+	for i, hex := range opts.Seeds {
+		norm, err := scheme.ParseColor(hex)
+		if err != nil {
+			return nil, fmt.Errorf("seed %q: %w", hex, err)
+		}
+		seeds[i], _ = colorful.Hex("#" + norm)
+	}
+	switch opts.Variant {
+	case VariantDark, VariantLight:
+	case "":
+		opts.Variant = VariantDark
+	default:
+		return nil, fmt.Errorf("unknown variant %q (expected dark or light)", opts.Variant)
+	}
+	if opts.Strategy == "" {
+		opts.Strategy = StrategyMaterial
+	}
+
+	dark := opts.Variant == VariantDark
+	primaryHue, primaryChroma, _ := seeds[0].Hcl()
+	primaryChroma *= 100
+
+	s := &scheme.Base16{
+		System:  "base16",
+		Name:    opts.Name,
+		Author:  "base16changer",
+		Variant: opts.Variant,
+	}
+	if opts.Base24 {
+		s.System = "base24"
+	}
+
+	// Neutral ramp: material neutral palette (chroma 4), with the outline
+	// and variant slots from the neutral-variant palette (chroma 8)
+	tones := lightTones
+	if dark {
+		tones = darkTones
+	}
+	for i, t := range tones {
+		c := 4.0
+		if i == 3 || i == 4 {
+			c = 8
+		}
+		s.Palette.Set(scheme.Base16Slots[i], tone(primaryHue, c, t))
+	}
+
+	accentTone, brownTone := 40.0, 35.0
+	if dark {
+		accentTone, brownTone = 80, 60
+	}
+
+	hues := map[string]float64{}
+	chromas := map[string]float64{}
+	switch opts.Strategy {
+	case StrategyMaterial:
+		for _, a := range accentHues {
+			hues[a.slot] = harmonize(a.hue, primaryHue)
+			chromas[a.slot] = 48
+		}
+		hues["base08"], chromas["base08"] = 25, 84 // error palette
+		hues["base0D"], chromas["base0D"] = primaryHue, math.Max(48, primaryChroma)
+		hues["base0C"], chromas["base0C"] = primaryHue, 16                   // secondary
+		hues["base0E"], chromas["base0E"] = math.Mod(primaryHue+60, 360), 24 // tertiary
+		for i, slot := range []string{"base0C", "base0E"} {
+			if i+1 < len(seeds) {
+				h, c, _ := seeds[i+1].Hcl()
+				hues[slot], chromas[slot] = h, math.Max(16, c*100)
+			}
+		}
+
+	case StrategyAnalogous, StrategyTriadic, StrategyComplementary:
+		var targets []float64
+		for _, seed := range seeds {
+			h, _, _ := seed.Hcl()
+			targets = append(targets, harmony(opts.Strategy, h)...)
+		}
+		for _, a := range accentHues {
+			hues[a.slot] = pullToward(a.hue, targets)
+			chromas[a.slot] = math.Max(40, primaryChroma)
+		}
+		// Each seed sets the hue and chroma of the accent slot nearest it
+		for _, seed := range seeds {
+			h, c, _ := seed.Hcl()
+			slot := nearestAccent(h)
+			hues[slot], chromas[slot] = h, math.Max(16, c*100)
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown strategy %q (expected one of %v)", opts.Strategy, Strategies)
+	}
+
+	for _, a := range accentHues {
+		s.Palette.Set(a.slot, tone(hues[a.slot], chromas[a.slot], accentTone))
+	}
+	s.Palette.Set("base0F", tone(hues["base09"], chromas["base09"]*0.6, brownTone))
+
+	if opts.Base24 {
+		bgTones := [2]float64{99, 100}
+		if dark {
+			bgTones = [2]float64{4, 2}
+		}
+		s.Palette.Set("base10", tone(primaryHue, 4, bgTones[0]))
+		s.Palette.Set("base11", tone(primaryHue, 4, bgTones[1]))
+
+		bright := 8.0 // brights are lighter on dark schemes, darker on light ones
+		if !dark {
+			bright = -8
+		}
+		for _, pair := range [][2]string{
+			{"base12", "base08"}, {"base13", "base0A"}, {"base14", "base0B"},
+			{"base15", "base0C"}, {"base16", "base0D"}, {"base17", "base0E"},
+		} {
+			c, _ := colorful.Hex(s.Palette.Hex(pair[1]))
+			h, ch, l := c.Hcl()
+			s.Palette.Set(pair[0], tone(h, ch*100, l*100+bright))
+		}
+	}
+
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// tone returns the CIE LCh color with the given hue, chroma (0–~130) and
+// tone (L*, 0–100) as hex, lowering chroma until it fits in sRGB
+func tone(hue, chroma, t float64) string {
+	t = clamp(t, 0, 100)
+	col := colorful.Hcl(hue, chroma/100, t/100)
+	for chroma > 0 && !col.IsValid() {
+		chroma = math.Max(0, chroma-1)
+		col = colorful.Hcl(hue, chroma/100, t/100)
+	}
+	return col.Clamped().Hex()
+}
+
+// harmonize rotates hue up to 15° toward source, like Material's custom
+// color harmonization
+func harmonize(hue, source float64) float64 {
+	d := hueDelta(hue, source)
+	return math.Mod(hue+math.Copysign(math.Min(math.Abs(d)*0.5, 15), d)+360, 360)
+}
+
+// harmony returns the hues a strategy derives from one seed hue
+func harmony(strategy string, h float64) []float64 {
+	switch strategy {
+	case StrategyAnalogous:
+		return []float64{h, h - 30, h + 30}
+	case StrategyTriadic:
+		return []float64{h, h + 120, h + 240}
+	default: // complementary
+		return []float64{h, h + 180}
+	}
+}
+
+// pullToward moves hue halfway to the nearest target, at most 30°
+func pullToward(hue float64, targets []float64) float64 {
+	best := 0.0
+	for i, t := range targets {
+		if d := hueDelta(hue, t); i == 0 || math.Abs(d) < math.Abs(best) {
+			best = d
+		}
+	}
+	return math.Mod(hue+math.Copysign(math.Min(math.Abs(best)/2, 30), best)+360, 360)
+}
+
+// nearestAccent returns the accent slot whose standard hue is closest to h
+func nearestAccent(h float64) string {
+	best, bestD := "", math.Inf(1)
+	for _, a := range accentHues {
+		if d := math.Abs(hueDelta(a.hue, h)); d < bestD {
+			best, bestD = a.slot, d
+		}
+	}
+	return best
+}
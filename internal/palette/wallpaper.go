@@ -0,0 +1,162 @@
+package palette
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"github.com/jaycee1285/base16changer/internal/scheme"
+)
+
+// Variants accepted by FromWallpaper
+const (
+	VariantAuto  = "auto"
+	VariantDark  = "dark"
+	VariantLight = "light"
+)
+
+// wallpaperClusters is how many colors are extracted from a wallpaper
+const wallpaperClusters = 16
+
+// Lightness ladders for base00–base07 in OKLCH
+var (
+	darkRamp  = [8]float64{0.19, 0.24, 0.30, 0.50, 0.78, 0.91, 0.94, 0.97}
+	lightRamp = [8]float64{0.97, 0.92, 0.87, 0.63, 0.45, 0.25, 0.20, 0.16}
+)
+
+// accent is an accent slot and the OKLCH hue it should land near
+type accent struct {
+	slot string
+	hue  float64
+}
+
+var accents = []accent{
+	{"base08", 25},  // red
+	{"base09", 55},  // orange
+	{"base0A", 95},  // yellow
+	{"base0B", 140}, // green
+	{"base0C", 195}, // cyan
+	{"base0D", 255}, // blue
+	{"base0E", 320}, // purple
+}
+
+// FromWallpaper extracts a base16 scheme from an image. The background
+// ramp is tinted with the wallpaper's dominant color; each accent takes
+// the hue of the closest strong color in the image, or its standard hue
+// when the image has none nearby. variant is dark, light or auto (judged
+// by the image's average lightness).
+func FromWallpaper(path, variant string) (*scheme.Base16, error) {
+	clusters, err := Extract(path, wallpaperClusters)
+	if err != nil {
+		return nil, err
+	}
+
+	switch variant {
+	case VariantDark, VariantLight:
+	case VariantAuto, "":
+		variant = VariantDark
+		var l float64
+		for _, c := range clusters {
+			l += c.L * c.Weight
+		}
+		if l > 0.6 {
+			variant = VariantLight
+		}
+	default:
+		return nil, fmt.Errorf("unknown variant %q (expected dark, light or auto)", variant)
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	s := &scheme.Base16{
+		System:      "base16",
+		Name:        "Wallpaper " + name,
+		Author:      "base16changer",
+		Description: "Generated from " + filepath.Base(path),
+		Variant:     variant,
+	}
+
+	// Neutral ramp, tinted by the dominant color
+	dominant := clusters[0]
+	ramp, accentL := darkRamp, [2]float64{0.68, 0.82}
+	if variant == VariantLight {
+		ramp, accentL = lightRamp, [2]float64{0.42, 0.56}
+	}
+	tint := math.Min(dominant.Chroma(), 0.035)
+	for i, l := range ramp {
+		c := tint
+		if i >= 4 {
+			c = tint / 2 // foregrounds carry less tint than backgrounds
+		}
+		s.Palette.Set(scheme.Base16Slots[i], scheme.FromOKLCH(l, c, dominant.Hue()))
+	}
+
+	// Accents
+	fallbackChroma := typicalChroma(clusters)
+	var orange [3]float64
+	for _, a := range accents {
+		l, c, h := (accentL[0]+accentL[1])/2, fallbackChroma, a.hue
+		if best, ok := closestHue(clusters, a.hue); ok {
+			l = clamp(best.L, accentL[0], accentL[1])
+			c = clamp(best.Chroma(), 0.08, 0.2)
+			h = a.hue + hueDelta(a.hue, best.Hue())/2 // stay clear of neighboring accents
+		}
+		s.Palette.Set(a.slot, scheme.FromOKLCH(l, c, h))
+		if a.slot == "base09" {
+			orange = [3]float64{l, c, h}
+		}
+	}
+
+	// Brown: a darker, duller orange
+	s.Palette.Set("base0F", scheme.FromOKLCH(orange[0]-0.15, orange[1]*0.6, orange[2]))
+
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// closestHue returns the heaviest reasonably colorful cluster within 40°
+// of hue, favoring closer and more saturated ones
+func closestHue(clusters []Cluster, hue float64) (Cluster, bool) {
+	var best Cluster
+	bestScore := 0.0
+	for _, c := range clusters {
+		if c.Chroma() < 0.04 {
+			continue
+		}
+		d := math.Abs(hueDelta(hue, c.Hue()))
+		if d > 40 {
+			continue
+		}
+		if score := (c.Weight + 0.01) * c.Chroma() / (1 + d/15); score > bestScore {
+			best, bestScore = c, score
+		}
+	}
+	return best, bestScore > 0
+}
+
+// typicalChroma is the weighted chroma of the image's colorful clusters,
+// used for accents the image has no color for
+func typicalChroma(clusters []Cluster) float64 {
+	var sum, weight float64
+	for _, c := range clusters {
+		if c.Chroma() >= 0.04 {
+			sum += c.Chroma() * c.Weight
+			weight += c.Weight
+		}
+	}
+	if weight == 0 {
+		return 0.12
+	}
+	return clamp(sum/weight, 0.08, 0.16)
+}
+
+// hueDelta returns the signed shortest angle from a to b in degrees
+func hueDelta(a, b float64) float64 {
+	return math.Mod(b-a+540, 360) - 180
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
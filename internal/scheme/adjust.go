@@ -0,0 +1,185 @@
+package scheme
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// Adjustment operations accepted by ParseAdjustments
+const (
+	AdjustHue         = "hue"         // rotate OKLCH hue by degrees
+	AdjustSaturation  = "saturation"  // scale OKLCH chroma by a percentage
+	AdjustBrightness  = "brightness"  // add percentage points to OKLCH lightness
+	AdjustTemperature = "temperature" // shift white balance by kelvin (positive is warmer)
+	AdjustContrast    = "contrast"    // spread lightness around the background/text midpoint
+)
+
+// adjustUnits lists each operation's unit; the unit may be omitted
+var adjustUnits = map[string]string{
+	AdjustHue:         "deg",
+	AdjustSaturation:  "%",
+	AdjustBrightness:  "%",
+	AdjustTemperature: "K",
+	AdjustContrast:    "%",
+}
+
+// Adjustment is one palette transform, such as saturation=-15%
+type Adjustment struct {
+	Op    string
+	Value float64
+}
+
+func (a Adjustment) String() string {
+	return fmt.Sprintf("%s=%+g%s", a.Op, a.Value, adjustUnits[a.Op])
+}
+
+// FormatAdjustments is the inverse of ParseAdjustments
+func FormatAdjustments(adjs []Adjustment) string {
+	parts := make([]string, len(adjs))
+	for i, a := range adjs {
+		parts[i] = a.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// ParseAdjustments parses a comma-separated list of op=value transforms,
+// e.g. "saturation=-15%,temperature=+500K,hue=20deg". Ops are applied in
+// the order given.
+func ParseAdjustments(spec string) ([]Adjustment, error) {
+	var out []Adjustment
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		op, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("adjustment %q: expected op=value", part)
+		}
+		op = strings.ToLower(strings.TrimSpace(op))
+		unit, known := adjustUnits[op]
+		if !known {
+			return nil, fmt.Errorf("adjustment %q: unknown op %q (expected hue, saturation, brightness, temperature or contrast)", part, op)
+		}
+		value = strings.TrimSpace(value)
+		value = strings.TrimSuffix(strings.TrimSuffix(value, unit), strings.ToLower(unit))
+		if op == AdjustHue {
+			value = strings.TrimSuffix(value, "°")
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("adjustment %q: %q is not a number", part, value)
+		}
+		if op == AdjustSaturation && v < -100 {
+			return nil, fmt.Errorf("adjustment %q: saturation cannot drop below -100%%", part)
+		}
+		out = append(out, Adjustment{Op: op, Value: v})
+	}
+	return out, nil
+}
+
+// Adjust returns a copy of s with the transforms applied in order to every
+// color the scheme defines. Hue, saturation, brightness and contrast work
+// in OKLCH; temperature scales OKLab's cone (LMS) response toward the
+// white point of the target color temperature. Colors that leave sRGB lose
+// chroma rather than hue. The scheme's file is not touched.
+func (s *Base16) Adjust(adjs []Adjustment) *Base16 {
+	out := *s
+	for _, a := range adjs {
+		pivot := 0.5
+		if a.Op == AdjustContrast {
+			bg, _ := colorful.Hex(out.Palette.Hex("base00"))
+			fg, _ := colorful.Hex(out.Palette.Hex("base05"))
+			lb, _, _ := bg.OkLch()
+			lf, _, _ := fg.OkLch()
+			pivot = (lb + lf) / 2
+		}
+		for _, name := range Base24Slots {
+			hex := out.Palette.Get(name)
+			if hex == "" {
+				continue
+			}
+			out.Palette.Set(name, adjustColor(hex, a, pivot))
+		}
+	}
+	return &out
+}
+
+func adjustColor(hex string, a Adjustment, pivot float64) string {
+	c, err := colorful.Hex("#" + hex)
+	if err != nil {
+		return hex
+	}
+	if a.Op == AdjustTemperature {
+		c = whiteBalance(c, a.Value)
+	}
+
+	l, chroma, hue := c.OkLch()
+	switch a.Op {
+	case AdjustHue:
+		hue = math.Mod(hue+a.Value+360, 360)
+	case AdjustSaturation:
+		chroma *= 1 + a.Value/100
+	case AdjustBrightness:
+		l += a.Value / 100
+	case AdjustContrast:
+		l = pivot + (l-pivot)*(1+a.Value/100)
+	}
+	return FromOKLCH(math.Max(0, math.Min(1, l)), math.Max(0, chroma), hue)
+}
+
+// d65 is the color temperature of sRGB white, the zero point of
+// temperature adjustments
+const d65 = 6504
+
+// whiteBalance shifts c as if the light source moved kelvin toward a warmer
+// (positive) or cooler (negative) color temperature, with a von Kries scale
+// in OKLab's LMS space
+func whiteBalance(c colorful.Color, kelvin float64) colorful.Color {
+	target := math.Max(1667, math.Min(25000, d65-kelvin))
+	tl, tm, ts := whiteLMS(target)
+	dl, dm, ds := whiteLMS(d65)
+
+	r, g, b := c.LinearRgb()
+	l := 0.4122214708*r + 0.5363325363*g + 0.0514459929*b
+	m := 0.2119034982*r + 0.6806995451*g + 0.1073969566*b
+	s := 0.0883024619*r + 0.2817188376*g + 0.6299787005*b
+	l, m, s = l*tl/dl, m*tm/dm, s*ts/ds
+	return colorful.LinearRgb(
+		+4.0767416621*l-3.3077115913*m+0.2309699292*s,
+		-1.2684380046*l+2.6097574011*m-0.3413193965*s,
+		-0.0041960863*l-0.7034186147*m+1.7076147010*s,
+	)
+}
+
+// whiteLMS returns the OKLab cone response of a Planckian white of the
+// given temperature (1667–25000 K), normalized to Y = 1
+func whiteLMS(kelvin float64) (l, m, s float64) {
+	t := kelvin
+	// Kim et al. cubic spline approximation of the Planckian locus
+	var x float64
+	if t <= 4000 {
+		x = -0.2661239e9/(t*t*t) - 0.2343589e6/(t*t) + 0.8776956e3/t + 0.179910
+	} else {
+		x = -3.0258469e9/(t*t*t) + 2.1070379e6/(t*t) + 0.2226347e3/t + 0.240390
+	}
+	var y float64
+	switch {
+	case t <= 2222:
+		y = -1.1063814*x*x*x - 1.34811020*x*x + 2.18555832*x - 0.20219683
+	case t <= 4000:
+		y = -0.9549476*x*x*x - 1.37418593*x*x + 2.09137015*x - 0.16748867
+	default:
+		y = 3.0817580*x*x*x - 5.87338670*x*x + 3.75112997*x - 0.37001483
+	}
+
+	X, Y, Z := x/y, 1.0, (1-x-y)/y
+	l = 0.8189330101*X + 0.3618667424*Y - 0.1288597137*Z
+	m = 0.0329845436*X + 0.9293118715*Y + 0.0361456387*Z
+	s = 0.0482003018*X + 0.2643662691*Y + 0.6338517070*Z
+	return l, m, s
+}
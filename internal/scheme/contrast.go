@@ -0,0 +1,78 @@
+package scheme
+
+import "math"
+
+// ContrastRatio returns the WCAG 2.x contrast ratio (1–21) between two
+// 6-digit hex colors
+func ContrastRatio(fg, bg string) float64 {
+	l1, l2 := relativeLuminance(fg), relativeLuminance(bg)
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+	return (l1 + 0.05) / (l2 + 0.05)
+}
+
+// relativeLuminance is the WCAG 2.x luminance of an sRGB hex color
+func relativeLuminance(hex string) float64 {
+	r, g, b := hexToRGB(hex)
+	lin := func(v uint8) float64 {
+		c := float64(v) / 255
+		if c <= 0.04045 {
+			return c / 12.92
+		}
+		return math.Pow((c+0.055)/1.055, 2.4)
+	}
+	return 0.2126*lin(r) + 0.7152*lin(g) + 0.0722*lin(b)
+}
+
+// APCA-W3 0.0.98G-4g constants
+const (
+	apcaNormBG                = 0.56
+	apcaNormTxt               = 0.57
+	apcaRevTxt                = 0.62
+	apcaRevBG                 = 0.65
+	apcaBlkThrs               = 0.022
+	apcaBlkClmp               = 1.414
+	apcaScale                 = 1.14
+	apcaLoOffset              = 0.027
+	apcaLoClip                = 0.1
+	apcaDeltaYMin             = 0.0005
+	apcaTRC                   = 2.4
+	apcaRco, apcaGco, apcaBco = 0.2126729, 0.7151522, 0.0721750
+)
+
+// APCAContrast returns the APCA lightness contrast (Lc, roughly -108 to
+// 106) of text color fg on background bg. Positive values are dark text on
+// a light background, negative values light text on a dark background.
+func APCAContrast(fg, bg string) float64 {
+	yTxt, yBG := apcaY(fg), apcaY(bg)
+	if math.Abs(yBG-yTxt) < apcaDeltaYMin {
+		return 0
+	}
+
+	var lc float64
+	if yBG > yTxt {
+		sapc := (math.Pow(yBG, apcaNormBG) - math.Pow(yTxt, apcaNormTxt)) * apcaScale
+		if sapc >= apcaLoClip {
+			lc = sapc - apcaLoOffset
+		}
+	} else {
+		sapc := (math.Pow(yBG, apcaRevBG) - math.Pow(yTxt, apcaRevTxt)) * apcaScale
+		if sapc <= -apcaLoClip {
+			lc = sapc + apcaLoOffset
+		}
+	}
+	return lc * 100
+}
+
+// apcaY is the APCA screen luminance of a hex color, with the soft black clamp
+func apcaY(hex string) float64 {
+	r, g, b := hexToRGB(hex)
+	y := apcaRco*math.Pow(float64(r)/255, apcaTRC) +
+		apcaGco*math.Pow(float64(g)/255, apcaTRC) +
+		apcaBco*math.Pow(float64(b)/255, apcaTRC)
+	if y < apcaBlkThrs {
+		y += math.Pow(apcaBlkThrs-y, apcaBlkClmp)
+	}
+	return y
+}
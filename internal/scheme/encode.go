@@ -0,0 +1,50 @@
+package scheme
+
+import (
+	"bytes"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Marshal encodes the scheme in the tinted-theming YAML layout that Parse
+// reads. base10–base17 are only written for base24 schemes.
+func (s *Base16) Marshal() ([]byte, error) {
+	doc := &yaml.Node{Kind: yaml.MappingNode}
+	add := func(m *yaml.Node, key, value string) {
+		m.Content = append(m.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: key},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: value, Style: yaml.DoubleQuotedStyle})
+	}
+
+	add(doc, "system", s.SystemName())
+	add(doc, "name", s.Name)
+	if s.Slug != "" {
+		add(doc, "slug", s.Slug)
+	}
+	add(doc, "author", s.Author)
+	if s.Description != "" {
+		add(doc, "description", s.Description)
+	}
+	add(doc, "variant", s.VariantName())
+
+	slots := Base16Slots
+	if s.Palette.IsBase24() {
+		slots = Base24Slots
+	}
+	palette := &yaml.Node{Kind: yaml.MappingNode}
+	for _, name := range slots {
+		add(palette, name, s.Palette.Hex(name))
+	}
+	doc.Content = append(doc.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "palette"}, palette)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
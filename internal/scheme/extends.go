@@ -0,0 +1,191 @@
+package scheme
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// OriginOverride is what Origin reports for slots set by Override
+const OriginOverride = "--override"
+
+// Resolver maps the scheme name in an extends key to its file
+type Resolver func(name string) (string, error)
+
+// ParseResolve is Parse with parent schemes that are not next to the child
+// looked up through resolve, which may be nil. Inheritance cycles are
+// reported as errors.
+func ParseResolve(path string, resolve Resolver) (*Base16, error) {
+	return parseChain(path, resolve, nil)
+}
+
+// parseChain parses path and, recursively, what it extends. seen holds the
+// canonical paths of the children already being resolved.
+func parseChain(path string, resolve Resolver, seen []string) (*Base16, error) {
+	abs := canonicalPath(path)
+	for i, p := range seen {
+		if p == abs {
+			var names []string
+			for _, q := range append(seen[i:], abs) {
+				names = append(names, filepath.Base(q))
+			}
+			return nil, fmt.Errorf("parse scheme %s: extends cycle: %s", seen[0], strings.Join(names, " → "))
+		}
+	}
+	seen = append(seen[:len(seen):len(seen)], abs)
+
+	s, err := parseFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s.Chain = []string{path}
+	if s.Extends == "" {
+		return s, nil
+	}
+
+	parentPath, err := findParent(path, s.Extends, resolve)
+	if err != nil {
+		return nil, fmt.Errorf("parse scheme %s: extends %q: %w", path, s.Extends, err)
+	}
+	parent, err := parseChain(parentPath, resolve, seen)
+	if err != nil {
+		return nil, err
+	}
+	s.inherit(parent)
+
+	if err := s.Validate(); err != nil {
+		var verr *ValidationError
+		if errors.As(err, &verr) {
+			verr.File = path
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// findParent locates the scheme named by child's extends key: a path
+// (relative to the child) when it has a directory or .yaml/.yml suffix,
+// otherwise <name>.yaml or .yml next to the child, then resolve. The child
+// itself is skipped, so a scheme can extend the one it shadows.
+func findParent(child, name string, resolve Resolver) (string, error) {
+	dir := filepath.Dir(child)
+	if strings.ContainsRune(name, '/') || strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml") {
+		p := name
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(dir, p)
+		}
+		if _, err := os.Stat(p); err != nil {
+			return "", err
+		}
+		return p, nil
+	}
+
+	self := canonicalPath(child)
+	for _, ext := range []string{".yaml", ".yml"} {
+		if p := filepath.Join(dir, name+ext); fileExists(p) && canonicalPath(p) != self {
+			return p, nil
+		}
+	}
+	if resolve != nil {
+		p, err := resolve(name)
+		if err == nil && canonicalPath(p) == self {
+			return "", fmt.Errorf("%s is the scheme itself; give the parent's path instead", name)
+		}
+		return p, err
+	}
+	return "", fmt.Errorf("no %s.yaml next to %s", name, filepath.Base(child))
+}
+
+// inherit fills everything s leaves unset from parent and appends the
+// parent's chain to s's
+func (s *Base16) inherit(parent *Base16) {
+	if s.Name == "" {
+		s.Name = parent.Name
+		if s.Slug == "" {
+			s.Slug = parent.Slug
+		}
+	}
+	for _, f := range []struct{ dst, src *string }{
+		{&s.System, &parent.System},
+		{&s.Author, &parent.Author},
+		{&s.Description, &parent.Description},
+		{&s.Variant, &parent.Variant},
+	} {
+		if *f.dst == "" {
+			*f.dst = *f.src
+		}
+	}
+
+	s.origin = map[string]string{}
+	for _, name := range Base24Slots {
+		key := strings.ToLower(name)
+		if s.Palette.Get(name) != "" {
+			s.origin[key] = s.Chain[0]
+		} else if v := parent.Palette.Get(name); v != "" {
+			s.Palette.Set(name, v)
+			s.origin[key] = parent.Origin(name)
+		}
+	}
+	s.Chain = append(s.Chain, parent.Chain...)
+}
+
+// Override sets one slot from a "slot=color" spec such as
+// "base0D=#5e81ac", as given to --override
+func (s *Base16) Override(spec string) error {
+	name, value, ok := strings.Cut(spec, "=")
+	name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+	if !ok || value == "" {
+		return fmt.Errorf("override %q: expected slot=color", spec)
+	}
+	p := s.Palette.slot(name)
+	if p == nil {
+		return fmt.Errorf("override %q: unknown slot %q (expected base00–base0F or base10–base17)", spec, name)
+	}
+	hex, err := ParseColor(value)
+	if err != nil {
+		return fmt.Errorf("override %q: %w", spec, err)
+	}
+	*p = hex
+
+	// Copy: s may share the map with the scheme it was copied from
+	origin := make(map[string]string, len(s.origin)+1)
+	for k, v := range s.origin {
+		origin[k] = v
+	}
+	origin[strings.ToLower(name)] = OriginOverride
+	s.origin = origin
+	return nil
+}
+
+// Origin returns the file a slot's color came from, OriginOverride for
+// slots set by Override, or "" for base24 slots left to their fallback
+func (s *Base16) Origin(name string) string {
+	if s.Palette.Get(name) == "" {
+		return ""
+	}
+	if o, ok := s.origin[strings.ToLower(name)]; ok {
+		return o
+	}
+	if len(s.Chain) > 0 {
+		return s.Chain[0]
+	}
+	return ""
+}
+
+// canonicalPath makes path absolute with symlinks resolved, for cycle checks
+func canonicalPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		path = real
+	}
+	return path
+}
+
+func fileExists(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && !fi.IsDir()
+}
@@ -1,8 +1,6 @@
 package scheme
 
 import (
-	"os"
-
 	"github.com/lucasb-eyer/go-colorful"
 	"gopkg.in/yaml.v3"
 )
@@ -36,22 +34,39 @@ type Gogh struct {
 	Cursor     string `yaml:"cursor"`
 }
 
-// parseGogh reads a Gogh YAML scheme file
-func parseGogh(path string) (*Gogh, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
+// ANSI returns pointers to the 16 ANSI colors in terminal order (color0
+// black … color15 bright white), for converters to fill or read by index
+func (g *Gogh) ANSI() []*string {
+	return []*string{
+		&g.Color01, &g.Color02, &g.Color03, &g.Color04,
+		&g.Color05, &g.Color06, &g.Color07, &g.Color08,
+		&g.Color09, &g.Color10, &g.Color11, &g.Color12,
+		&g.Color13, &g.Color14, &g.Color15, &g.Color16,
 	}
+}
+
+// Validate checks that the 16 ANSI colors, background and foreground are
+// set and normalizes every color, like Base16.Validate
+func (g *Gogh) Validate() error {
+	return g.validate(nil)
+}
 
+// parseGogh decodes and validates a Gogh YAML theme and converts it to
+// base16; lines maps its keys to their line in the file
+func parseGogh(root *yaml.Node, lines map[string]int) (*Base16, error) {
 	var g Gogh
-	if err := yaml.Unmarshal(data, &g); err != nil {
+	if err := root.Decode(&g); err != nil {
+		return nil, err
+	}
+	if err := g.validate(lines); err != nil {
 		return nil, err
 	}
 
-	return &g, nil
+	return g.ToBase16(), nil
 }
 
-// ToBase16 converts a Gogh scheme to Base16 format
+// ToBase16 converts a Gogh scheme to Base16 format. Gogh's bright ANSI
+// colors fill the base24 slots, so the result is a base24 scheme.
 func (g *Gogh) ToBase16() *Base16 {
 	bg := normalizeColor(g.Background)
 	fg := normalizeColor(g.Foreground)
@@ -67,31 +82,62 @@ func (g *Gogh) ToBase16() *Base16 {
 	brown := blendColors(orange, bg, 0.4)
 
 	return &Base16{
-		System:  "base16",
+		System:  "base24",
 		Name:    g.Name,
 		Author:  g.Author,
 		Variant: g.Variant,
+		Format:  FormatGogh,
 		Palette: Colors{
-			Base00: bg,                                    // Background
-			Base01: interpolate(bg, fg, 0.1),              // Lighter bg
-			Base02: interpolate(bg, fg, 0.2),              // Selection bg
-			Base03: normalizeColor(g.Color09),             // Bright black (comments)
-			Base04: interpolate(bg, fg, 0.4),              // Dark fg
-			Base05: fg,                                    // Foreground
-			Base06: interpolate(bg, fg, 0.8),              // Light fg
-			Base07: normalizeColor(g.Color16),             // Bright white
-			Base08: red,                                   // Red
-			Base09: orange,                                // Orange (derived)
-			Base0A: yellow,                                // Yellow
-			Base0B: normalizeColor(g.Color03),             // Green
-			Base0C: normalizeColor(g.Color07),             // Cyan
-			Base0D: normalizeColor(g.Color05),             // Blue
-			Base0E: normalizeColor(g.Color06),             // Magenta
-			Base0F: brown,                                 // Brown (derived)
+			Base00: bg,                        // Background
+			Base01: interpolate(bg, fg, 0.1),  // Lighter bg
+			Base02: interpolate(bg, fg, 0.2),  // Selection bg
+			Base03: normalizeColor(g.Color09), // Bright black (comments)
+			Base04: interpolate(bg, fg, 0.4),  // Dark fg
+			Base05: fg,                        // Foreground
+			Base06: interpolate(bg, fg, 0.8),  // Light fg
+			Base07: normalizeColor(g.Color16), // Bright white
+			Base08: red,                       // Red
+			Base09: orange,                    // Orange (derived)
+			Base0A: yellow,                    // Yellow
+			Base0B: normalizeColor(g.Color03), // Green
+			Base0C: normalizeColor(g.Color07), // Cyan
+			Base0D: normalizeColor(g.Color05), // Blue
+			Base0E: normalizeColor(g.Color06), // Magenta
+			Base0F: brown,                     // Brown (derived)
+
+			Base10: interpolate(bg, "000000", 0.2), // Darker bg
+			Base11: interpolate(bg, "000000", 0.4), // Darkest bg
+			Base12: normalizeColor(g.Color10),      // Bright red
+			Base13: normalizeColor(g.Color12),      // Bright yellow
+			Base14: normalizeColor(g.Color11),      // Bright green
+			Base15: normalizeColor(g.Color15),      // Bright cyan
+			Base16: normalizeColor(g.Color13),      // Bright blue
+			Base17: normalizeColor(g.Color14),      // Bright magenta
 		},
 	}
 }
 
+// ToGogh maps the scheme onto the 16 ANSI colors the way the kitty
+// template does, the inverse of ToBase16 for every slot it reads. base16
+// schemes use their normal colors as brights.
+func (s *Base16) ToGogh() *Gogh {
+	hex := s.Palette.Hex
+	return &Gogh{
+		Name:    s.Name,
+		Author:  s.Author,
+		Variant: s.VariantName(),
+
+		Color01: hex("base00"), Color02: hex("base08"), Color03: hex("base0B"), Color04: hex("base0A"),
+		Color05: hex("base0D"), Color06: hex("base0E"), Color07: hex("base0C"), Color08: hex("base05"),
+		Color09: hex("base03"), Color10: hex("base12"), Color11: hex("base14"), Color12: hex("base13"),
+		Color13: hex("base16"), Color14: hex("base17"), Color15: hex("base15"), Color16: hex("base07"),
+
+		Background: hex("base00"),
+		Foreground: hex("base05"),
+		Cursor:     hex("base05"),
+	}
+}
+
 // interpolate blends two hex colors by factor t (0.0 = c1, 1.0 = c2)
 func interpolate(c1, c2 string, t float64) string {
 	col1, err1 := colorful.Hex("#" + c1)
@@ -0,0 +1,52 @@
+package scheme
+
+import (
+	"math"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// lightnessStep is how far AdjustLightness moves OKLCH L per try
+const lightnessStep = 0.005
+
+// AdjustLightness moves color's OKLCH lightness away from against, keeping
+// hue and chroma, until ok accepts the result. It returns the first
+// accepted color, or the most extreme one tried and false when even pure
+// lightness changes cannot satisfy ok.
+func AdjustLightness(color, against string, ok func(hex string) bool) (string, bool) {
+	if ok(color) {
+		return color, true
+	}
+	c, err1 := colorful.Hex("#" + color)
+	a, err2 := colorful.Hex("#" + against)
+	if err1 != nil || err2 != nil {
+		return color, false
+	}
+
+	l, chroma, hue := c.OkLch()
+	al, _, _ := a.OkLch()
+	dir := 1.0 // lighten
+	if l < al || (l == al && al > 0.5) {
+		dir = -1
+	}
+
+	hex := color
+	for l = l + dir*lightnessStep; l >= 0 && l <= 1; l += dir * lightnessStep {
+		hex = FromOKLCH(l, chroma, hue)
+		if ok(hex) {
+			return hex, true
+		}
+	}
+	return FromOKLCH(math.Max(0, math.Min(1, l)), chroma, hue), false
+}
+
+// FromOKLCH converts OKLCH to a 6-digit hex color, reducing chroma until
+// the color fits in sRGB so hue is preserved
+func FromOKLCH(l, chroma, hue float64) string {
+	col := colorful.OkLch(l, chroma, hue)
+	for chroma > 0 && !col.IsValid() {
+		chroma = math.Max(0, chroma-0.005)
+		col = colorful.OkLch(l, chroma, hue)
+	}
+	return normalizeColor(col.Clamped().Hex())
+}
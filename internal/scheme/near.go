@@ -0,0 +1,98 @@
+package scheme
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// NearColor is one color of a similarity search, optionally pinned to a
+// slot. An empty Slot matches whichever slot of the scheme is closest.
+type NearColor struct {
+	Slot string
+	Hex  string // 6 hex digits, no #
+}
+
+func (n NearColor) String() string {
+	if n.Slot == "" {
+		return "#" + n.Hex
+	}
+	return n.Slot + "=#" + n.Hex
+}
+
+// ParseNear reads a "base00=#1d2021" or "#1d2021" spec, as given to --near
+func ParseNear(spec string) (NearColor, error) {
+	var n NearColor
+	value := strings.TrimSpace(spec)
+	if name, v, ok := strings.Cut(value, "="); ok {
+		n.Slot = canonicalSlot(strings.TrimSpace(name))
+		if n.Slot == "" {
+			return n, fmt.Errorf("near %q: unknown slot %q (expected base00–base0F or base10–base17)", spec, strings.TrimSpace(name))
+		}
+		value = strings.TrimSpace(v)
+	}
+	hex, err := ParseColor(value)
+	if err != nil {
+		return n, fmt.Errorf("near %q: %w", spec, err)
+	}
+	n.Hex = hex
+	return n, nil
+}
+
+// canonicalSlot returns the spelling of a slot name used in Base24Slots,
+// or "" for unknown names
+func canonicalSlot(name string) string {
+	for _, slot := range Base24Slots {
+		if strings.EqualFold(slot, name) {
+			return slot
+		}
+	}
+	return ""
+}
+
+// Slots returns the slots the scheme defines: base00–base0F, plus
+// base10–base17 for base24 schemes
+func (s *Base16) Slots() []string {
+	if s.SystemName() == "base24" {
+		return Base24Slots
+	}
+	return Base16Slots
+}
+
+// SlotDistance is how far one searched color is from its slot
+type SlotDistance struct {
+	Query    string  `json:"query"`
+	Slot     string  `json:"slot"`
+	Hex      string  `json:"hex"`
+	Distance float64 `json:"distance"`
+}
+
+// Near measures the CIEDE2000 distance (ΔE00, 0–100) from each searched
+// color to the scheme: to the pinned slot, or to the closest slot when the
+// color is not pinned. Pinned base24 slots resolve to their fallbacks on
+// base16 schemes. The score is the mean distance.
+func (s *Base16) Near(colors []NearColor) (score float64, dists []SlotDistance) {
+	for _, n := range colors {
+		want, _ := colorful.Hex("#" + n.Hex)
+		d := SlotDistance{Query: n.String(), Distance: -1}
+		slots := s.Slots()
+		if n.Slot != "" {
+			slots = []string{n.Slot}
+		}
+		for _, slot := range slots {
+			hex := s.Palette.Resolved(slot)
+			c, _ := colorful.Hex("#" + hex)
+			dist := 100 * want.DistanceCIEDE2000(c)
+			if d.Distance < 0 || dist < d.Distance {
+				d.Slot, d.Hex, d.Distance = slot, hex, dist
+			}
+		}
+		dists = append(dists, d)
+		score += d.Distance
+	}
+	if len(dists) > 0 {
+		score /= float64(len(dists))
+	}
+	return score, dists
+}
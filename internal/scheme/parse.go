@@ -1,24 +1,38 @@
 package scheme
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
 
+	"github.com/lucasb-eyer/go-colorful"
 	"gopkg.in/yaml.v3"
 )
 
-// Base16 represents a base16 color scheme
+// Base16 represents a base16 or base24 color scheme
 type Base16 struct {
-	System  string `yaml:"system"`
-	Name    string `yaml:"name"`
-	Author  string `yaml:"author"`
-	Variant string `yaml:"variant"` // "light" or "dark"
-	Palette Colors `yaml:"palette"`
+	System      string `yaml:"system"`
+	Name        string `yaml:"name"`
+	Slug        string `yaml:"slug"` // optional, derived from Name when empty
+	Author      string `yaml:"author"`
+	Description string `yaml:"description"`
+	Variant     string `yaml:"variant"` // "light" or "dark"
+	Palette     Colors `yaml:"palette"`
+
+	// Extends names a parent scheme whose metadata and colors fill in
+	// anything this one leaves unset
+	Extends string `yaml:"extends"`
+
+	Format Format   `yaml:"-"` // layout the scheme was parsed from
+	Chain  []string `yaml:"-"` // files the scheme was resolved from, this one first
+
+	lines  map[string]int    // lowercased slot → line in the file; "" → palette block
+	origin map[string]string // slot → file (or "--override") it was taken from
 }
 
-// Colors holds the 16 base colors
+// Colors holds the 16 base colors, plus the 8 extra base24 colors
 type Colors struct {
 	Base00 string `yaml:"base00"` // Default Background
 	Base01 string `yaml:"base01"` // Lighter Background (status bars)
@@ -36,159 +50,360 @@ type Colors struct {
 	Base0D string `yaml:"base0D"` // Blue
 	Base0E string `yaml:"base0E"` // Purple
 	Base0F string `yaml:"base0F"` // Brown
+
+	// base24 only
+	Base10 string `yaml:"base10"` // Darker Background
+	Base11 string `yaml:"base11"` // Darkest Background
+	Base12 string `yaml:"base12"` // Bright Red
+	Base13 string `yaml:"base13"` // Bright Yellow
+	Base14 string `yaml:"base14"` // Bright Green
+	Base15 string `yaml:"base15"` // Bright Cyan
+	Base16 string `yaml:"base16"` // Bright Blue
+	Base17 string `yaml:"base17"` // Bright Purple
+}
+
+// Base16Slots lists the base16 color names in order
+var Base16Slots = []string{
+	"base00", "base01", "base02", "base03", "base04", "base05", "base06", "base07",
+	"base08", "base09", "base0A", "base0B", "base0C", "base0D", "base0E", "base0F",
+}
+
+// Base24Slots lists the base24 color names in order (base16 plus base10–base17)
+var Base24Slots = append(append([]string{}, Base16Slots...),
+	"base10", "base11", "base12", "base13", "base14", "base15", "base16", "base17",
+)
+
+// base24Fallback maps each base24-only slot to the base16 slot used when a
+// scheme does not define it
+var base24Fallback = map[string]string{
+	"base10": "base00",
+	"base11": "base00",
+	"base12": "base08",
+	"base13": "base0A",
+	"base14": "base0B",
+	"base15": "base0C",
+	"base16": "base0D",
+	"base17": "base0E",
 }
 
-// Parse reads a base16 or Gogh YAML scheme file (auto-detects format)
+// Format identifies the file layout a scheme was parsed from
+type Format string
+
+const (
+	FormatTinted Format = "tinted" // system/name/palette layout used by tinted-theming
+	FormatLegacy Format = "legacy" // original flat layout: scheme, author, base00… at top level
+	FormatGogh   Format = "gogh"   // Gogh terminal theme, converted on load
+)
+
+// legacyScheme is the original base16 layout with colors at the top level
+type legacyScheme struct {
+	Scheme  string `yaml:"scheme"`
+	Author  string `yaml:"author"`
+	Extends string `yaml:"extends"`
+	Colors  `yaml:",inline"`
+}
+
+// Parse reads a base16 or base24 scheme file in the tinted-theming or
+// legacy layout, or a Gogh YAML theme (auto-detects format). Colors are
+// validated and normalized; invalid ones are reported as a
+// *ValidationError naming the file, line and slot. A scheme that extends
+// another is resolved against a parent file in the same directory; use
+// ParseResolve to search elsewhere.
 func Parse(path string) (*Base16, error) {
+	return ParseResolve(path, nil)
+}
+
+// parseFile parses one scheme file without resolving what it extends
+func parseFile(path string) (*Base16, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("read scheme: %w", err)
 	}
 
-	var scheme Base16
-	if err := yaml.Unmarshal(data, &scheme); err != nil {
-		return nil, fmt.Errorf("parse scheme: %w", err)
+	s, err := parseData(data)
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		verr.File = path
+		return nil, verr
 	}
+	if err != nil {
+		return nil, fmt.Errorf("parse scheme %s: %w", path, err)
+	}
+	return s, nil
+}
 
-	// Normalize colors (remove # prefix if present, lowercase)
-	scheme.Palette.normalize()
+// parseData detects the layout of a scheme file's contents, parses it and
+// validates the colors
+func parseData(data []byte) (*Base16, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	var doc *yaml.Node
+	if len(root.Content) > 0 {
+		doc = root.Content[0]
+	}
+	top := keyLines(doc)
+	_, hasPalette := top["palette"]
+	_, hasBase00 := top["base00"]
+	_, hasGogh := top["color_01"]
+	_, hasExtends := top["extends"]
 
-	// Auto-detect Gogh format: missing base09 (orange) or base0F (brown).
-	// Backup heuristic: Gogh uses .yml, Base16 uses .yaml (not enforced here).
-	if scheme.Palette.Base09 == "" || scheme.Palette.Base0F == "" {
-		gogh, err := parseGogh(path)
-		if err == nil && gogh.Color01 != "" {
-			return gogh.ToBase16(), nil
+	var scheme *Base16
+	switch {
+	case hasPalette, hasExtends && !hasBase00:
+		scheme = &Base16{}
+		if err := root.Decode(scheme); err != nil {
+			return nil, err
 		}
-	}
 
-	return &scheme, nil
-}
+		// Auto-detect Gogh format: missing base09 (orange) or base0F (brown).
+		// Backup heuristic: Gogh uses .yml, Base16 uses .yaml (not enforced here).
+		if hasGogh && (scheme.Palette.Base09 == "" || scheme.Palette.Base0F == "") {
+			return parseGogh(&root, top)
+		}
+		scheme.Format = FormatTinted
+		scheme.lines = keyLines(mappingValue(doc, "palette"))
+		scheme.lines[""] = top["palette"]
 
-func (c *Colors) normalize() {
-	c.Base00 = normalizeColor(c.Base00)
-	c.Base01 = normalizeColor(c.Base01)
-	c.Base02 = normalizeColor(c.Base02)
-	c.Base03 = normalizeColor(c.Base03)
-	c.Base04 = normalizeColor(c.Base04)
-	c.Base05 = normalizeColor(c.Base05)
-	c.Base06 = normalizeColor(c.Base06)
-	c.Base07 = normalizeColor(c.Base07)
-	c.Base08 = normalizeColor(c.Base08)
-	c.Base09 = normalizeColor(c.Base09)
-	c.Base0A = normalizeColor(c.Base0A)
-	c.Base0B = normalizeColor(c.Base0B)
-	c.Base0C = normalizeColor(c.Base0C)
-	c.Base0D = normalizeColor(c.Base0D)
-	c.Base0E = normalizeColor(c.Base0E)
-	c.Base0F = normalizeColor(c.Base0F)
-}
+	case hasBase00:
+		var legacy legacyScheme
+		if err := root.Decode(&legacy); err != nil {
+			return nil, err
+		}
+		scheme = &Base16{
+			Name:    legacy.Scheme,
+			Author:  legacy.Author,
+			Extends: legacy.Extends,
+			Palette: legacy.Colors,
+			Format:  FormatLegacy,
+			lines:   top,
+		}
 
-func normalizeColor(c string) string {
-	c = strings.TrimPrefix(c, "#")
-	c = strings.ToLower(c)
-	return c
+	case hasGogh:
+		return parseGogh(&root, top)
+
+	default:
+		return nil, fmt.Errorf("unrecognized scheme format (expected a palette: block, top-level base00… keys or Gogh color_01… keys)")
+	}
+
+	// A child scheme may leave slots to its parent; they are checked once
+	// the chain is resolved
+	if err := scheme.validate(scheme.Extends == ""); err != nil {
+		return nil, err
+	}
+	if scheme.Format == FormatLegacy && scheme.Extends == "" {
+		scheme.System = "base16"
+		if scheme.Palette.IsBase24() {
+			scheme.System = "base24"
+		}
+	}
+	return scheme, nil
 }
 
-// Hex returns the color with # prefix
-func (c *Colors) Hex(name string) string {
-	switch name {
+// slot returns a pointer to the named color (case-insensitive), or nil
+func (c *Colors) slot(name string) *string {
+	switch strings.ToLower(name) {
 	case "base00":
-		return "#" + c.Base00
+		return &c.Base00
 	case "base01":
-		return "#" + c.Base01
+		return &c.Base01
 	case "base02":
-		return "#" + c.Base02
+		return &c.Base02
 	case "base03":
-		return "#" + c.Base03
+		return &c.Base03
 	case "base04":
-		return "#" + c.Base04
+		return &c.Base04
 	case "base05":
-		return "#" + c.Base05
+		return &c.Base05
 	case "base06":
-		return "#" + c.Base06
+		return &c.Base06
 	case "base07":
-		return "#" + c.Base07
+		return &c.Base07
 	case "base08":
-		return "#" + c.Base08
+		return &c.Base08
 	case "base09":
-		return "#" + c.Base09
-	case "base0A", "base0a":
-		return "#" + c.Base0A
-	case "base0B", "base0b":
-		return "#" + c.Base0B
-	case "base0C", "base0c":
-		return "#" + c.Base0C
-	case "base0D", "base0d":
-		return "#" + c.Base0D
-	case "base0E", "base0e":
-		return "#" + c.Base0E
-	case "base0F", "base0f":
-		return "#" + c.Base0F
+		return &c.Base09
+	case "base0a":
+		return &c.Base0A
+	case "base0b":
+		return &c.Base0B
+	case "base0c":
+		return &c.Base0C
+	case "base0d":
+		return &c.Base0D
+	case "base0e":
+		return &c.Base0E
+	case "base0f":
+		return &c.Base0F
+	case "base10":
+		return &c.Base10
+	case "base11":
+		return &c.Base11
+	case "base12":
+		return &c.Base12
+	case "base13":
+		return &c.Base13
+	case "base14":
+		return &c.Base14
+	case "base15":
+		return &c.Base15
+	case "base16":
+		return &c.Base16
+	case "base17":
+		return &c.Base17
 	default:
-		return ""
+		return nil
 	}
 }
 
-// hexToDec converts a 6-char hex color string to decimal R, G, B strings
-func hexToDec(hex string) (string, string, string) {
-	r, _ := strconv.ParseUint(hex[0:2], 16, 8)
-	g, _ := strconv.ParseUint(hex[2:4], 16, 8)
-	b, _ := strconv.ParseUint(hex[4:6], 16, 8)
-	return strconv.FormatUint(r, 10), strconv.FormatUint(g, 10), strconv.FormatUint(b, 10)
+// Get returns the named color as 6 hex digits without #, or "" if unknown
+func (c *Colors) Get(name string) string {
+	if p := c.slot(name); p != nil {
+		return *p
+	}
+	return ""
+}
+
+// Set stores a color in the named slot; it reports false for unknown names
+func (c *Colors) Set(name, hex string) bool {
+	p := c.slot(name)
+	if p == nil {
+		return false
+	}
+	*p = normalizeColor(hex)
+	return true
+}
+
+// IsBase24 reports whether all of base10–base17 are defined
+func (c *Colors) IsBase24() bool {
+	for _, name := range Base24Slots[16:] {
+		if c.Get(name) == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// Resolved returns the color for a slot, falling back to the matching
+// base16 color for base24-only slots the scheme does not define
+func (c *Colors) Resolved(name string) string {
+	if v := c.Get(name); v != "" {
+		return v
+	}
+	if fb, ok := base24Fallback[strings.ToLower(name)]; ok {
+		return c.Get(fb)
+	}
+	return ""
+}
+
+// FallbackSlot returns the base16 slot a base24-only slot falls back to,
+// or "" for base16 slots
+func FallbackSlot(name string) string {
+	return base24Fallback[strings.ToLower(name)]
 }
 
-// ToMap returns colors as a map for template rendering
+func normalizeColor(c string) string {
+	c = strings.TrimPrefix(c, "#")
+	c = strings.ToLower(c)
+	return c
+}
+
+// Hex returns the color with # prefix
+func (c *Colors) Hex(name string) string {
+	if v := c.Resolved(name); v != "" {
+		return "#" + v
+	}
+	return ""
+}
+
+// hexToRGB converts a 6-char hex color string to 0–255 R, G, B values
+func hexToRGB(hex string) (r, g, b uint8) {
+	if len(hex) != 6 {
+		return 0, 0, 0
+	}
+	rv, _ := strconv.ParseUint(hex[0:2], 16, 8)
+	gv, _ := strconv.ParseUint(hex[2:4], 16, 8)
+	bv, _ := strconv.ParseUint(hex[4:6], 16, 8)
+	return uint8(rv), uint8(gv), uint8(bv)
+}
+
+// SystemName returns the scheme system, inferring base24 from the palette
+// when the scheme does not say
+func (s *Base16) SystemName() string {
+	if s.System != "" {
+		return s.System
+	}
+	if s.Palette.IsBase24() {
+		return "base24"
+	}
+	return "base16"
+}
+
+// SlugName returns the scheme's slug, derived from its name when unset
+func (s *Base16) SlugName() string {
+	if s.Slug != "" {
+		return slugify(s.Slug)
+	}
+	return slugify(s.Name)
+}
+
+// VariantName returns "dark" or "light", judging by the background's
+// lightness when the scheme does not say
+func (s *Base16) VariantName() string {
+	switch strings.ToLower(s.Variant) {
+	case "dark", "light":
+		return strings.ToLower(s.Variant)
+	}
+	bg, err := colorful.Hex("#" + s.Palette.Base00)
+	if err == nil {
+		if l, _, _ := bg.Lab(); l > 0.5 {
+			return "light"
+		}
+	}
+	return "dark"
+}
+
+// ToMap returns the tinted-theming builder variables for template
+// rendering. base10–base17 are always present; base16 schemes get the
+// base24 fallback colors.
 func (s *Base16) ToMap() map[string]string {
+	variant := s.VariantName()
+	slug := s.SlugName()
 	m := map[string]string{
-		"scheme-name":   s.Name,
-		"scheme-author": s.Author,
-		"scheme-slug":   slugify(s.Name),
-		"base00-hex":    s.Palette.Base00,
-		"base01-hex":    s.Palette.Base01,
-		"base02-hex":    s.Palette.Base02,
-		"base03-hex":    s.Palette.Base03,
-		"base04-hex":    s.Palette.Base04,
-		"base05-hex":    s.Palette.Base05,
-		"base06-hex":    s.Palette.Base06,
-		"base07-hex":    s.Palette.Base07,
-		"base08-hex":    s.Palette.Base08,
-		"base09-hex":    s.Palette.Base09,
-		"base0A-hex":    s.Palette.Base0A,
-		"base0B-hex":    s.Palette.Base0B,
-		"base0C-hex":    s.Palette.Base0C,
-		"base0D-hex":    s.Palette.Base0D,
-		"base0E-hex":    s.Palette.Base0E,
-		"base0F-hex":    s.Palette.Base0F,
-	}
-
-	// Add decimal R, G, B values for each base color
-	bases := []struct {
-		name string
-		hex  string
-	}{
-		{"base00", s.Palette.Base00}, {"base01", s.Palette.Base01},
-		{"base02", s.Palette.Base02}, {"base03", s.Palette.Base03},
-		{"base04", s.Palette.Base04}, {"base05", s.Palette.Base05},
-		{"base06", s.Palette.Base06}, {"base07", s.Palette.Base07},
-		{"base08", s.Palette.Base08}, {"base09", s.Palette.Base09},
-		{"base0A", s.Palette.Base0A}, {"base0B", s.Palette.Base0B},
-		{"base0C", s.Palette.Base0C}, {"base0D", s.Palette.Base0D},
-		{"base0E", s.Palette.Base0E}, {"base0F", s.Palette.Base0F},
-	}
-	for _, b := range bases {
-		r, g, bl := hexToDec(b.hex)
-		m[b.name+"-dec-r"] = r
-		m[b.name+"-dec-g"] = g
-		m[b.name+"-dec-b"] = bl
+		"scheme-system":           s.SystemName(),
+		"scheme-name":             s.Name,
+		"scheme-author":           s.Author,
+		"scheme-description":      s.Description,
+		"scheme-slug":             slug,
+		"scheme-slug-underscored": strings.ReplaceAll(slug, "-", "_"),
+		"scheme-variant":          variant,
+		"scheme-is-dark-variant":  strconv.FormatBool(variant == "dark"),
+		"scheme-is-light-variant": strconv.FormatBool(variant == "light"),
+	}
+
+	for _, name := range Base24Slots {
+		hex := s.Palette.Resolved(name)
+		r, g, b := hexToRGB(hex)
+		rh, gh, bh := fmt.Sprintf("%02x", r), fmt.Sprintf("%02x", g), fmt.Sprintf("%02x", b)
+
+		m[name+"-hex"] = hex
+		m[name+"-hex-bgr"] = bh + gh + rh
+		m[name+"-hex-r"] = rh
+		m[name+"-hex-g"] = gh
+		m[name+"-hex-b"] = bh
+		m[name+"-rgb-r"] = strconv.Itoa(int(r))
+		m[name+"-rgb-g"] = strconv.Itoa(int(g))
+		m[name+"-rgb-b"] = strconv.Itoa(int(b))
+		m[name+"-dec-r"] = formatDec(r)
+		m[name+"-dec-g"] = formatDec(g)
+		m[name+"-dec-b"] = formatDec(b)
 	}
 
 	return m
 }
 
-func slugify(name string) string {
-	s := strings.ToLower(name)
-	s = strings.ReplaceAll(s, " ", "-")
-	s = strings.ReplaceAll(s, ",", "")
-	return s
+// formatDec formats a 0–255 channel as a 0–1 float
+func formatDec(v uint8) string {
+	return strconv.FormatFloat(float64(v)/255, 'f', 8, 64)
 }
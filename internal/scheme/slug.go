@@ -0,0 +1,60 @@
+package scheme
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Latin letters with diacritics and their plain ASCII letter, position by
+// position. Letters that need more than one ASCII letter are in translitMulti.
+const (
+	translitFrom = "àáâãäåçèéêëìíîïñòóôõöùúûüýÿāăąćĉċčďēĕėęěĝğġģĥĩīĭįĵķĺļľńņňōŏőŕŗřśŝşšţťũūŭůűųŵŷźżžơưǎǐǒǔǖǘǚǜǟǡǧǩǫǭǰǵǹǻȁȃȅȇȉȋȍȏȑȓȕȗșțȟȧȩȫȭȯȱȳ"
+	translitTo   = "aaaaaaceeeeiiiinooooouuuuyyaaaccccdeeeeegggghiiiijklllnnnooorrrssssttuuuuuuwyzzzouaiouuuuuaagkoojgnaaaeeiioorruusthaeooooy"
+)
+
+var translitMulti = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'þ': "th", 'ð': "d", 'ø': "o",
+	'ł': "l", 'đ': "d", 'ħ': "h", 'ı': "i", 'ŋ': "ng", 'ĳ': "ij",
+	'ŀ': "l", 'ŉ': "n", 'ſ': "s", 'ŧ': "t", 'ĸ': "k",
+}
+
+var translit = func() map[rune]string {
+	m := make(map[rune]string, len(translitMulti)+len(translitFrom))
+	to := []rune(translitTo)
+	for i, r := range []rune(translitFrom) {
+		m[r] = string(to[i])
+	}
+	for r, s := range translitMulti {
+		m[r] = s
+	}
+	return m
+}()
+
+// slugify turns a scheme name into a lowercase, dash-separated slug.
+// Accented Latin letters are transliterated to ASCII, other letters and
+// digits are kept, apostrophes are dropped and any other run of
+// punctuation, symbols or whitespace becomes a single dash.
+func slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		var out string
+		switch {
+		case translit[r] != "":
+			out = translit[r]
+		case unicode.Is(unicode.Mn, r), r == '\'', r == '’':
+			continue // combining marks and apostrophes vanish
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			out = string(r)
+		default:
+			dash = b.Len() > 0
+			continue
+		}
+		if dash {
+			b.WriteByte('-')
+			dash = false
+		}
+		b.WriteString(out)
+	}
+	return b.String()
+}
@@ -0,0 +1,151 @@
+package scheme
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SlotError describes one missing or invalid color in a scheme
+type SlotError struct {
+	Line  int    // line in the scheme file, 0 if unknown
+	Slot  string // e.g. "base0A" or "color_03"
+	Value string // raw value from the file, "" when missing
+	Err   error
+}
+
+func (e *SlotError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d: %s", e.Line, e.message())
+	}
+	return e.message()
+}
+
+func (e *SlotError) message() string {
+	if e.Value != "" {
+		return fmt.Sprintf("%s: %q: %v", e.Slot, e.Value, e.Err)
+	}
+	return fmt.Sprintf("%s: %v", e.Slot, e.Err)
+}
+
+// ValidationError lists every problem found in one scheme file
+type ValidationError struct {
+	File   string
+	Errors []*SlotError
+}
+
+// Error formats one problem per line as file:line: slot: message
+func (e *ValidationError) Error() string {
+	lines := make([]string, len(e.Errors))
+	for i, se := range e.Errors {
+		switch {
+		case e.File != "" && se.Line > 0:
+			lines[i] = fmt.Sprintf("%s:%d: %s", e.File, se.Line, se.message())
+		case e.File != "":
+			lines[i] = e.File + ": " + se.message()
+		default:
+			lines[i] = se.Error()
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+var errMissing = fmt.Errorf("missing (every base16 slot from base00 to base0F is required)")
+
+// Validate checks that base00–base0F are set and every defined color
+// parses, normalizing short hex, rgb() and CSS color names to 6 hex digits
+func (s *Base16) Validate() error {
+	return s.validate(true)
+}
+
+// validate normalizes every defined color; requireAll also reports
+// missing base16 slots
+func (s *Base16) validate(requireAll bool) error {
+	var errs []*SlotError
+	for i, name := range Base24Slots {
+		p := s.Palette.slot(name)
+		if *p == "" {
+			if requireAll && i < len(Base16Slots) {
+				errs = append(errs, &SlotError{Line: s.lines[""], Slot: name, Err: errMissing})
+			}
+			continue
+		}
+		hex, err := ParseColor(*p)
+		if err != nil {
+			errs = append(errs, &SlotError{Line: s.lines[strings.ToLower(name)], Slot: name, Value: *p, Err: err})
+			continue
+		}
+		*p = hex
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// validate normalizes the Gogh colors in place, like Base16.Validate
+func (g *Gogh) validate(lines map[string]int) error {
+	fields := []struct {
+		key      string
+		p        *string
+		required bool
+	}{
+		{"color_01", &g.Color01, true}, {"color_02", &g.Color02, true},
+		{"color_03", &g.Color03, true}, {"color_04", &g.Color04, true},
+		{"color_05", &g.Color05, true}, {"color_06", &g.Color06, true},
+		{"color_07", &g.Color07, true}, {"color_08", &g.Color08, true},
+		{"color_09", &g.Color09, true}, {"color_10", &g.Color10, true},
+		{"color_11", &g.Color11, true}, {"color_12", &g.Color12, true},
+		{"color_13", &g.Color13, true}, {"color_14", &g.Color14, true},
+		{"color_15", &g.Color15, true}, {"color_16", &g.Color16, true},
+		{"background", &g.Background, true},
+		{"foreground", &g.Foreground, true},
+		{"cursor", &g.Cursor, false},
+	}
+
+	var errs []*SlotError
+	for _, f := range fields {
+		if *f.p == "" {
+			if f.required {
+				errs = append(errs, &SlotError{Slot: f.key, Err: fmt.Errorf("missing")})
+			}
+			continue
+		}
+		hex, err := ParseColor(*f.p)
+		if err != nil {
+			errs = append(errs, &SlotError{Line: lines[f.key], Slot: f.key, Value: *f.p, Err: err})
+			continue
+		}
+		*f.p = hex
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+// keyLines maps each (lowercased) key of a YAML mapping to its line
+func keyLines(node *yaml.Node) map[string]int {
+	lines := map[string]int{}
+	if node == nil || node.Kind != yaml.MappingNode {
+		return lines
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		lines[strings.ToLower(node.Content[i].Value)] = node.Content[i].Line
+	}
+	return lines
+}
+
+// mappingValue returns the value node for key in a YAML mapping, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,332 @@
+package targets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultKeepGenerations is how many generations are kept when the config
+// file does not say otherwise
+const DefaultKeepGenerations = 20
+
+// Generation is a snapshot of every file one Apply run touched, taken
+// before the run changed them
+type Generation struct {
+	ID      int       `json:"id"`
+	Time    time.Time `json:"time"`
+	Scheme  string    `json:"scheme"`
+	Targets []string  `json:"targets"`
+	Files   []Backup  `json:"files"`
+
+	dir string
+}
+
+// Backup records the state of one file before a run touched it
+type Backup struct {
+	Path    string      `json:"path"`
+	Existed bool        `json:"existed"`
+	Mode    fs.FileMode `json:"mode,omitempty"`
+	Saved   string      `json:"saved,omitempty"` // copy inside the generation dir
+}
+
+// StateDir returns $XDG_STATE_HOME/base16changer (or ~/.local/state/base16changer)
+func StateDir() string {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "base16changer")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".local/state/base16changer")
+}
+
+func generationsDir() string {
+	return filepath.Join(StateDir(), "generations")
+}
+
+// ListGenerations returns all saved generations, oldest first
+func ListGenerations() ([]*Generation, error) {
+	entries, err := os.ReadDir(generationsDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var gens []*Generation
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		dir := filepath.Join(generationsDir(), e.Name())
+		data, err := os.ReadFile(filepath.Join(dir, "manifest.json"))
+		if err != nil {
+			continue // incomplete generation from a crashed run
+		}
+		var g Generation
+		if err := json.Unmarshal(data, &g); err != nil {
+			return nil, fmt.Errorf("generation %s: %w", e.Name(), err)
+		}
+		g.dir = dir
+		gens = append(gens, &g)
+	}
+	sort.Slice(gens, func(i, j int) bool { return gens[i].ID < gens[j].ID })
+	return gens, nil
+}
+
+// beginGeneration starts a new generation numbered after the latest one
+func beginGeneration(scheme string) (*Generation, error) {
+	gens, err := ListGenerations()
+	if err != nil {
+		return nil, err
+	}
+	id := 1
+	if len(gens) > 0 {
+		id = gens[len(gens)-1].ID + 1
+	}
+
+	now := time.Now()
+	dir := filepath.Join(generationsDir(), fmt.Sprintf("%04d-%s", id, now.Format("20060102T150405")))
+	if err := os.MkdirAll(filepath.Join(dir, "files"), 0755); err != nil {
+		return nil, fmt.Errorf("create generation: %w", err)
+	}
+	return &Generation{ID: id, Time: now, Scheme: scheme, dir: dir}, nil
+}
+
+// snapshot saves the current state of path, once per generation
+func (g *Generation) snapshot(path string) error {
+	for _, b := range g.Files {
+		if b.Path == path {
+			return nil
+		}
+	}
+
+	b := Backup{Path: path}
+	info, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return fmt.Errorf("backup %s: %w", path, err)
+	default:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("backup %s: %w", path, err)
+		}
+		b.Existed = true
+		b.Mode = info.Mode().Perm()
+		b.Saved = filepath.Join("files", strconv.Itoa(len(g.Files)))
+		if err := os.WriteFile(filepath.Join(g.dir, b.Saved), data, 0600); err != nil {
+			return fmt.Errorf("backup %s: %w", path, err)
+		}
+	}
+	g.Files = append(g.Files, b)
+	return nil
+}
+
+// empty reports whether the generation touched nothing but the current
+// scheme record, which alone is not worth undoing
+func (g *Generation) empty() bool {
+	for _, b := range g.Files {
+		if b.Path != CurrentSchemePath() {
+			return false
+		}
+	}
+	return true
+}
+
+// commit writes the manifest, or discards the generation if nothing was touched
+func (g *Generation) commit(keep int) error {
+	if g.empty() {
+		return os.RemoveAll(g.dir)
+	}
+	data, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := atomicWrite(filepath.Join(g.dir, "manifest.json"), data, 0644); err != nil {
+		return err
+	}
+	return pruneGenerations(keep)
+}
+
+// pruneGenerations deletes all but the newest keep generations
+func pruneGenerations(keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+	gens, err := ListGenerations()
+	if err != nil {
+		return err
+	}
+	for len(gens) > keep {
+		if err := os.RemoveAll(gens[0].dir); err != nil {
+			return err
+		}
+		gens = gens[1:]
+	}
+	return nil
+}
+
+// restore puts every file back the way it was before the generation ran
+func (g *Generation) restore() error {
+	var errs []error
+	for i := len(g.Files) - 1; i >= 0; i-- {
+		b := g.Files[i]
+		if !b.Existed {
+			if err := os.Remove(b.Path); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(g.dir, b.Saved))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("restore %s: %w", b.Path, err))
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(b.Path), 0755); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := atomicWrite(b.Path, data, b.Mode); err != nil {
+			errs = append(errs, fmt.Errorf("restore %s: %w", b.Path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Undo reverts the most recent generation. The generation is deleted
+// afterwards and nothing records the undo, so there is no redo.
+func Undo(cfg *Config) (*Report, error) {
+	gens, err := ListGenerations()
+	if err != nil {
+		return nil, err
+	}
+	if len(gens) == 0 {
+		return nil, errors.New("nothing to undo: no generations saved")
+	}
+	latest := gens[len(gens)-1]
+	return revert(cfg, []*Generation{latest}, fmt.Sprintf("undo generation %d", latest.ID))
+}
+
+// Rollback reverts every generation newer than id, newest first, so the
+// files look the way they did right after generation id was applied
+// (id 0 means before the oldest recorded run). Reverted generations are
+// deleted and the reloads of their targets are re-run.
+func Rollback(cfg *Config, id int) (*Report, error) {
+	gens, err := ListGenerations()
+	if err != nil {
+		return nil, err
+	}
+	if id < 0 || (id > 0 && !hasGeneration(gens, id)) {
+		return nil, fmt.Errorf("no generation %d", id)
+	}
+
+	var newer []*Generation
+	for _, g := range gens {
+		if g.ID > id {
+			newer = append(newer, g)
+		}
+	}
+	if len(newer) == 0 {
+		return nil, fmt.Errorf("already at generation %d", id)
+	}
+	return revert(cfg, newer, fmt.Sprintf("rollback to generation %d", id))
+}
+
+// revert restores gens newest first, deletes them and re-runs the reloads
+// of every target they touched. The files it overwrites are not
+// snapshotted, so a revert cannot itself be reverted.
+func revert(cfg *Config, gens []*Generation, label string) (*Report, error) {
+	report := &Report{Scheme: label, DryRun: cfg.DryRun}
+	touched := map[string]bool{}
+	for i := len(gens) - 1; i >= 0; i-- {
+		g := gens[i]
+		if cfg.DryRun {
+			for _, b := range g.Files {
+				logf(cfg, "  Would restore: %s\n", b.Path)
+			}
+		} else {
+			if err := g.restore(); err != nil {
+				return nil, fmt.Errorf("generation %d: %w", g.ID, err)
+			}
+			if err := os.RemoveAll(g.dir); err != nil {
+				return nil, err
+			}
+		}
+		if cfg.DryRun {
+			logf(cfg, "  Would revert generation %d (%s)\n", g.ID, g.Scheme)
+		} else {
+			logf(cfg, "  [OK] reverted generation %d (%s)\n", g.ID, g.Scheme)
+		}
+		for _, name := range g.Targets {
+			touched[name] = true
+		}
+	}
+
+	logln(cfg, "\nTriggering reloads...")
+	cfg.gtkReloaded = false
+	for _, t := range cfg.Targets() {
+		if !touched[t.Name()] {
+			continue
+		}
+		res := Result{Target: t.Name(), Status: StatusOK}
+		reload(cfg, t, &res)
+		report.Results = append(report.Results, res)
+	}
+	return report, nil
+}
+
+func hasGeneration(gens []*Generation, id int) bool {
+	for _, g := range gens {
+		if g.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// atomicWrite writes data to a temp file next to path and renames it into
+// place, so readers never see a half-written file. Symlinks are followed so
+// the link itself is preserved.
+func atomicWrite(path string, data []byte, mode fs.FileMode) error {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	if mode == 0 {
+		mode = 0644
+		if info, err := os.Stat(path); err == nil {
+			mode = info.Mode().Perm()
+		}
+	}
+
+	dir, base := filepath.Split(path)
+	tmp, err := os.CreateTemp(dir, "."+strings.TrimPrefix(base, ".")+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op after a successful rename
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
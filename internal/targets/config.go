@@ -0,0 +1,423 @@
+package targets
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/jaycee1285/base16changer/internal/scheme"
+	"gopkg.in/yaml.v3"
+)
+
+// fileConfig mirrors the on-disk config file. Every key is optional; unset
+// keys keep the value from DefaultConfig.
+type fileConfig struct {
+	Paths struct {
+		Kitty          string   `yaml:"kitty"`
+		Fuzzel         string   `yaml:"fuzzel"`
+		Gtk2           string   `yaml:"gtk2"`
+		Gtk3           string   `yaml:"gtk3"`
+		Gtk3User       string   `yaml:"gtk3-user"`
+		Gtk4           string   `yaml:"gtk4"`
+		Gtk4Theme      string   `yaml:"gtk4-theme"`
+		GtkSettings    []string `yaml:"gtk-settings"`
+		IndexTheme     string   `yaml:"index-theme"`
+		OpenboxThemerc string   `yaml:"openbox-themerc"`
+		LabwcRc        string   `yaml:"labwc-rc"`
+		Ferritebar     string   `yaml:"ferritebar"`
+		Templates      string   `yaml:"templates"`
+	} `yaml:"paths"`
+
+	Themes struct {
+		Openbox string `yaml:"openbox"`
+		Gtk     string `yaml:"gtk"`
+	} `yaml:"themes"`
+
+	Search struct {
+		Schemes    []string `yaml:"schemes"`
+		Icons      []string `yaml:"icons"`
+		Wallpapers string   `yaml:"wallpapers"`
+	} `yaml:"search"`
+
+	Defaults struct {
+		IconTheme string `yaml:"icon-theme"`
+		Wallpaper string `yaml:"wallpaper"`
+		Adjust    string `yaml:"adjust"`
+	} `yaml:"defaults"`
+
+	Targets struct {
+		Only   []string       `yaml:"only"`
+		Skip   []string       `yaml:"skip"`
+		Custom []customConfig `yaml:"custom"`
+	} `yaml:"targets"`
+
+	Tokens struct {
+		Dir     string   `yaml:"dir"`
+		Formats []string `yaml:"formats"`
+	} `yaml:"tokens"`
+
+	Backups struct {
+		Enabled *bool `yaml:"enabled"`
+		Keep    *int  `yaml:"keep"`
+	} `yaml:"backups"`
+}
+
+// customConfig is one entry of targets.custom
+type customConfig struct {
+	Name     string   `yaml:"name"`
+	Template string   `yaml:"template"`
+	Output   string   `yaml:"output"`
+	Mode     string   `yaml:"mode"`
+	Section  string   `yaml:"section"`
+	Markers  []string `yaml:"markers"`
+	Reload   string   `yaml:"reload"`
+	Signal   string   `yaml:"signal"`
+	Process  string   `yaml:"process"`
+}
+
+// ConfigDir returns $XDG_CONFIG_HOME/base16changer (or ~/.config/base16changer)
+func ConfigDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "base16changer")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config/base16changer")
+}
+
+// ConfigPath returns the default config file location
+func ConfigPath() string {
+	return filepath.Join(ConfigDir(), "config.yaml")
+}
+
+// LoadConfig applies settings from a YAML config file to cfg.
+// A missing file is only an error when required is set.
+func LoadConfig(cfg *Config, path string, required bool) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) && !required {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read config: %w", err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return fmt.Errorf("config %s: %w", path, err)
+	}
+	if len(root.Content) == 0 {
+		return nil // empty file
+	}
+	if errs := checkKeys(root.Content[0], reflect.TypeOf(fileConfig{}), ""); len(errs) > 0 {
+		return fmt.Errorf("config %s:\n  %s", path, strings.Join(errs, "\n  "))
+	}
+
+	var fc fileConfig
+	if err := root.Decode(&fc); err != nil {
+		return fmt.Errorf("config %s: %w", path, err)
+	}
+	if err := fc.apply(cfg); err != nil {
+		return fmt.Errorf("config %s:\n  %s", path, strings.ReplaceAll(err.Error(), "\n", "\n  "))
+	}
+	return nil
+}
+
+// apply copies every set value onto cfg, validating as it goes
+func (fc *fileConfig) apply(cfg *Config) error {
+	var errs []error
+	path := func(key, val string, dst *string) {
+		if val == "" {
+			return
+		}
+		p, err := expandPath(val)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+			return
+		}
+		*dst = p
+	}
+	paths := func(key string, vals []string, dst *[]string) {
+		if vals == nil {
+			return
+		}
+		out := make([]string, 0, len(vals))
+		for i, v := range vals {
+			p, err := expandPath(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s[%d]: %w", key, i, err))
+				continue
+			}
+			out = append(out, p)
+		}
+		*dst = out
+	}
+	name := func(key, val string, dst *string) {
+		if val == "" {
+			return
+		}
+		if strings.ContainsAny(val, "/\n") {
+			errs = append(errs, fmt.Errorf("%s: %q is not a valid theme name", key, val))
+			return
+		}
+		*dst = val
+	}
+
+	path("paths.kitty", fc.Paths.Kitty, &cfg.KittyThemeConf)
+	path("paths.fuzzel", fc.Paths.Fuzzel, &cfg.FuzzelIni)
+	path("paths.gtk2", fc.Paths.Gtk2, &cfg.Gtk2RC)
+	path("paths.gtk3", fc.Paths.Gtk3, &cfg.Gtk3CSS)
+	path("paths.gtk3-user", fc.Paths.Gtk3User, &cfg.Gtk3UserCSS)
+	path("paths.gtk4", fc.Paths.Gtk4, &cfg.Gtk4CSS)
+	path("paths.gtk4-theme", fc.Paths.Gtk4Theme, &cfg.Gtk4ThemeCSS)
+	paths("paths.gtk-settings", fc.Paths.GtkSettings, &cfg.GtkSettingsInis)
+	path("paths.index-theme", fc.Paths.IndexTheme, &cfg.IndexTheme)
+	path("paths.openbox-themerc", fc.Paths.OpenboxThemerc, &cfg.OpenboxThemerc)
+	path("paths.labwc-rc", fc.Paths.LabwcRc, &cfg.LabwcRcXml)
+	path("paths.ferritebar", fc.Paths.Ferritebar, &cfg.FerritebarConfig)
+	path("paths.templates", fc.Paths.Templates, &cfg.TemplatesDir)
+
+	name("themes.openbox", fc.Themes.Openbox, &cfg.OpenboxThemeName)
+	name("themes.gtk", fc.Themes.Gtk, &cfg.GtkThemeName)
+
+	paths("search.schemes", fc.Search.Schemes, &cfg.SchemeDirs)
+	paths("search.icons", fc.Search.Icons, &cfg.IconDirs)
+	path("search.wallpapers", fc.Search.Wallpapers, &cfg.WallpaperDir)
+
+	name("defaults.icon-theme", fc.Defaults.IconTheme, &cfg.IconTheme)
+	if fc.Defaults.Wallpaper != "" {
+		cfg.Wallpaper = fc.Defaults.Wallpaper
+	}
+	if fc.Defaults.Adjust != "" {
+		if adjs, err := scheme.ParseAdjustments(fc.Defaults.Adjust); err != nil {
+			errs = append(errs, fmt.Errorf("defaults.adjust: %w", err))
+		} else {
+			cfg.Adjust = adjs
+		}
+	}
+
+	if fc.Targets.Custom != nil {
+		custom, cerrs := parseCustomTargets(fc.Targets.Custom)
+		errs = append(errs, cerrs...)
+		cfg.Custom = custom
+	}
+	if _, err := nameSet(cfg, fc.Targets.Only); err != nil {
+		errs = append(errs, fmt.Errorf("targets.only: %w", err))
+	} else if len(fc.Targets.Only) > 0 {
+		cfg.Only = fc.Targets.Only
+	}
+	if _, err := nameSet(cfg, fc.Targets.Skip); err != nil {
+		errs = append(errs, fmt.Errorf("targets.skip: %w", err))
+	} else if len(fc.Targets.Skip) > 0 {
+		cfg.Skip = fc.Targets.Skip
+	}
+
+	path("tokens.dir", fc.Tokens.Dir, &cfg.TokensDir)
+	if fc.Tokens.Formats != nil {
+		if formats, err := ParseTokenFormats(fc.Tokens.Formats); err != nil {
+			errs = append(errs, fmt.Errorf("tokens.formats: %w", err))
+		} else {
+			cfg.TokenFormats = formats
+		}
+	}
+
+	if fc.Backups.Enabled != nil {
+		cfg.NoBackup = !*fc.Backups.Enabled
+	}
+	if fc.Backups.Keep != nil {
+		if *fc.Backups.Keep < 0 {
+			errs = append(errs, fmt.Errorf("backups.keep: must be 0 (keep all) or a positive count, got %d", *fc.Backups.Keep))
+		} else {
+			cfg.KeepGenerations = *fc.Backups.Keep
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// parseCustomTargets validates targets.custom entries. Names must be unique
+// and must not shadow a built-in target.
+func parseCustomTargets(entries []customConfig) ([]*CustomTarget, []error) {
+	var out []*CustomTarget
+	var errs []error
+	seen := map[string]bool{}
+	for i, e := range entries {
+		key := fmt.Sprintf("targets.custom[%d]", i)
+		fail := func(format string, args ...any) {
+			errs = append(errs, fmt.Errorf(key+": "+format, args...))
+		}
+
+		t := &CustomTarget{
+			TargetName: strings.TrimSpace(e.Name),
+			Mode:       e.Mode,
+			Section:    strings.TrimSpace(e.Section),
+			Command:    strings.TrimSpace(e.Reload),
+			Signal:     strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(e.Signal)), "SIG"),
+			Process:    strings.TrimSpace(e.Process),
+		}
+		switch {
+		case t.TargetName == "":
+			fail("name is required")
+			continue
+		case strings.ContainsAny(t.TargetName, ", /\t\n"):
+			fail("%q is not a valid target name", t.TargetName)
+			continue
+		case seen[t.TargetName]:
+			fail("target %q is declared twice", t.TargetName)
+			continue
+		}
+		if _, ok := Lookup(t.TargetName); ok {
+			fail("%q is a built-in target", t.TargetName)
+			continue
+		}
+		seen[t.TargetName] = true
+		key = "targets.custom." + t.TargetName
+
+		ok := true
+		switch tmpl := strings.TrimSpace(e.Template); {
+		case tmpl == "":
+			fail("template is required")
+			ok = false
+		case tmpl == "~" || strings.HasPrefix(tmpl, "~/") || filepath.IsAbs(os.ExpandEnv(tmpl)):
+			p, err := expandPath(tmpl)
+			if err != nil {
+				fail("template: %v", err)
+				ok = false
+			}
+			t.Template = p
+		default:
+			t.Template = filepath.Clean(os.ExpandEnv(tmpl))
+		}
+		if e.Output == "" {
+			fail("output is required")
+			ok = false
+		} else if p, err := expandPath(e.Output); err != nil {
+			fail("output: %v", err)
+			ok = false
+		} else {
+			t.Output = p
+		}
+
+		switch t.Mode {
+		case "":
+			t.Mode = ModeFull
+		case ModeFull, ModeIniSection, ModeMarkers:
+		default:
+			fail("mode %q is not one of %s, %s, %s", t.Mode, ModeFull, ModeIniSection, ModeMarkers)
+			ok = false
+		}
+		if t.Mode == ModeIniSection && t.Section == "" {
+			fail("mode %s needs a section", ModeIniSection)
+			ok = false
+		} else if t.Mode != ModeIniSection && t.Section != "" {
+			fail("section only applies to mode %s", ModeIniSection)
+			ok = false
+		}
+		switch {
+		case e.Markers == nil:
+			t.Begin, t.End = DefaultBeginMarker, DefaultEndMarker
+		case t.Mode != ModeMarkers:
+			fail("markers only apply to mode %s", ModeMarkers)
+			ok = false
+		case len(e.Markers) != 2:
+			fail("markers must be a begin and an end line, like [%q, %q]", DefaultBeginMarker, DefaultEndMarker)
+			ok = false
+		default:
+			t.Begin, t.End = strings.TrimSpace(e.Markers[0]), strings.TrimSpace(e.Markers[1])
+			if t.Begin == "" || t.End == "" || t.Begin == t.End {
+				fail("markers must be two different, non-empty lines")
+				ok = false
+			}
+		}
+
+		if t.Command != "" && t.Signal != "" {
+			fail("set reload or signal, not both")
+			ok = false
+		}
+		if t.Signal != "" {
+			if !validSignal(t.Signal) {
+				fail("signal %q is not a signal name like USR1 or HUP", e.Signal)
+				ok = false
+			}
+			if t.Process == "" {
+				t.Process = t.TargetName
+			}
+		} else if t.Process != "" {
+			fail("process only applies with signal")
+			ok = false
+		}
+
+		if ok {
+			out = append(out, t)
+		}
+	}
+	return out, errs
+}
+
+// validSignal accepts signal names as pkill takes them, without "SIG"
+func validSignal(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '+' && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// expandPath expands ~/ and environment variables and requires an absolute result
+func expandPath(p string) (string, error) {
+	p = os.ExpandEnv(strings.TrimSpace(p))
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		p = filepath.Join(home, strings.TrimPrefix(p, "~"))
+	}
+	if !filepath.IsAbs(p) {
+		return "", fmt.Errorf("%q must be an absolute path or start with ~/", p)
+	}
+	return filepath.Clean(p), nil
+}
+
+// checkKeys reports mapping keys in node that have no matching yaml tag in t
+func checkKeys(node *yaml.Node, t reflect.Type, prefix string) []string {
+	if node.Kind == yaml.SequenceNode && t.Kind() == reflect.Slice {
+		var errs []string
+		for i, item := range node.Content {
+			errs = append(errs, checkKeys(item, t.Elem(), fmt.Sprintf("%s[%d].", strings.TrimSuffix(prefix, "."), i))...)
+		}
+		return errs
+	}
+	if node.Kind != yaml.MappingNode || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := map[string]reflect.Type{}
+	var known []string
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+		if tag == "" || tag == "-" {
+			continue
+		}
+		fields[tag] = t.Field(i).Type
+		known = append(known, tag)
+	}
+
+	var errs []string
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, val := node.Content[i], node.Content[i+1]
+		ft, ok := fields[key.Value]
+		if !ok {
+			errs = append(errs, fmt.Sprintf("line %d: unknown key %q (expected one of: %s)",
+				key.Line, prefix+key.Value, strings.Join(known, ", ")))
+			continue
+		}
+		errs = append(errs, checkKeys(val, ft, prefix+key.Value+".")...)
+	}
+	return errs
+}
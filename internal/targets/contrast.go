@@ -0,0 +1,203 @@
+package targets
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/jaycee1285/base16changer/internal/scheme"
+)
+
+// Standard selects which contrast metric decides whether a pair passes
+type Standard string
+
+const (
+	StandardWCAG Standard = "wcag" // WCAG 2.x contrast ratio, AA level
+	StandardAPCA Standard = "apca" // APCA lightness contrast (Lc)
+)
+
+// Minimum contrast for text; secondary text (comments, inactive labels,
+// status bars) gets the large-text / non-body thresholds
+const (
+	MinRatio          = 4.5
+	MinRatioSecondary = 3.0
+	MinLc             = 60
+	MinLcSecondary    = 45
+)
+
+// ParseStandard accepts "wcag" or "apca"
+func ParseStandard(s string) (Standard, error) {
+	switch Standard(s) {
+	case StandardWCAG, StandardAPCA:
+		return Standard(s), nil
+	}
+	return "", fmt.Errorf("unknown contrast standard %q (expected wcag or apca)", s)
+}
+
+// ContrastPair is a text/background slot combination the built-in
+// templates render
+type ContrastPair struct {
+	Target    string `json:"target"`
+	Usage     string `json:"usage"`
+	FG        string `json:"fg"`
+	BG        string `json:"bg"`
+	Secondary bool   `json:"secondary,omitempty"`
+}
+
+// ContrastPairs lists the pairs checked by CheckContrast, matching the
+// kitty, fuzzel, openbox and GTK templates
+var ContrastPairs = []ContrastPair{
+	{Target: "kitty", Usage: "text", FG: "base05", BG: "base00"},
+	{Target: "kitty", Usage: "selection", FG: "base02", BG: "base05"},
+	{Target: "kitty", Usage: "comments", FG: "base03", BG: "base00", Secondary: true},
+	{Target: "kitty", Usage: "inactive tab", FG: "base04", BG: "base01", Secondary: true},
+	{Target: "fuzzel", Usage: "text", FG: "base05", BG: "base01"},
+	{Target: "fuzzel", Usage: "selection", FG: "base05", BG: "base03"},
+	{Target: "fuzzel", Usage: "match", FG: "base0D", BG: "base01"},
+	{Target: "openbox", Usage: "active label", FG: "base05", BG: "base01"},
+	{Target: "openbox", Usage: "inactive label", FG: "base03", BG: "base00", Secondary: true},
+	{Target: "openbox", Usage: "active menu item", FG: "base05", BG: "base02"},
+	{Target: "gtk", Usage: "view", FG: "base05", BG: "base00"},
+	{Target: "gtk", Usage: "headerbar", FG: "base05", BG: "base01"},
+	{Target: "gtk", Usage: "selection", FG: "base05", BG: "base02"},
+	{Target: "gtk", Usage: "accent button", FG: "base00", BG: "base0D"},
+}
+
+// PairResult is the measured contrast of one pair
+type PairResult struct {
+	ContrastPair
+	FGHex string  `json:"fg_hex"`
+	BGHex string  `json:"bg_hex"`
+	Ratio float64 `json:"ratio"`
+	Lc    float64 `json:"lc"`
+	Pass  bool    `json:"pass"`
+}
+
+// Required returns the threshold the pair had to meet under std
+func (r PairResult) Required(std Standard) float64 {
+	switch {
+	case std == StandardAPCA && r.Secondary:
+		return MinLcSecondary
+	case std == StandardAPCA:
+		return MinLc
+	case r.Secondary:
+		return MinRatioSecondary
+	default:
+		return MinRatio
+	}
+}
+
+// ContrastReport is the contrast audit of one scheme
+type ContrastReport struct {
+	Scheme   string       `json:"scheme"`
+	Standard Standard     `json:"standard"`
+	Pairs    []PairResult `json:"pairs"`
+}
+
+// CheckContrast measures every pair in ContrastPairs for s
+func CheckContrast(s *scheme.Base16, std Standard) *ContrastReport {
+	report := &ContrastReport{Scheme: s.Name, Standard: std}
+	for _, p := range ContrastPairs {
+		fg, bg := s.Palette.Resolved(p.FG), s.Palette.Resolved(p.BG)
+		res := PairResult{
+			ContrastPair: p,
+			FGHex:        fg,
+			BGHex:        bg,
+			Ratio:        scheme.ContrastRatio(fg, bg),
+			Lc:           scheme.APCAContrast(fg, bg),
+		}
+		if std == StandardAPCA {
+			res.Pass = math.Abs(res.Lc) >= res.Required(std)
+		} else {
+			res.Pass = res.Ratio >= res.Required(std)
+		}
+		report.Pairs = append(report.Pairs, res)
+	}
+	return report
+}
+
+// Failures returns the pairs that did not meet their threshold
+func (r *ContrastReport) Failures() []PairResult {
+	var out []PairResult
+	for _, p := range r.Pairs {
+		if !p.Pass {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Passed reports whether every pair met its threshold
+func (r *ContrastReport) Passed() bool {
+	return len(r.Failures()) == 0
+}
+
+// backgroundSlots are never changed by RepairContrast; the text color of
+// a pair is moved instead
+var backgroundSlots = map[string]bool{"base00": true, "base01": true, "base02": true}
+
+// ContrastFix is one slot changed by RepairContrast
+type ContrastFix struct {
+	Slot string `json:"slot"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// repairRounds bounds how often RepairContrast re-checks after a fix, since
+// changing a slot can affect other pairs that use it
+const repairRounds = 8
+
+// RepairContrast returns a copy of s with failing pairs fixed by moving
+// one color of each pair in OKLCH lightness, keeping its hue and chroma.
+// The text color is adjusted unless it is one of the background slots
+// (base00–base02), in which case the pair's other color moves. The final
+// audit lists anything that could not be fixed, for example when two pairs
+// need a shared slot to move in opposite directions.
+func RepairContrast(s *scheme.Base16, std Standard) (*scheme.Base16, []ContrastFix, *ContrastReport) {
+	out := *s
+	original := map[string]string{}
+
+	report := CheckContrast(&out, std)
+	for round := 0; round < repairRounds && !report.Passed(); round++ {
+		for _, p := range report.Failures() {
+			slot, other := p.FG, p.BG
+			if backgroundSlots[slot] {
+				slot, other = p.BG, p.FG
+			}
+			if backgroundSlots[slot] {
+				continue // both sides are backgrounds; leave the pair alone
+			}
+
+			required := p.Required(std)
+			otherHex := out.Palette.Resolved(other)
+			ok := func(hex string) bool {
+				fg, bg := hex, otherHex
+				if slot == p.BG {
+					fg, bg = otherHex, hex
+				}
+				if std == StandardAPCA {
+					return math.Abs(scheme.APCAContrast(fg, bg)) >= required
+				}
+				return scheme.ContrastRatio(fg, bg) >= required
+			}
+
+			current := out.Palette.Resolved(slot)
+			fixed, met := scheme.AdjustLightness(current, otherHex, ok)
+			if !met || fixed == current {
+				continue // out of reach: keep the original rather than push to black or white
+			}
+			if _, seen := original[slot]; !seen {
+				original[slot] = current
+			}
+			out.Palette.Set(slot, fixed)
+		}
+		report = CheckContrast(&out, std)
+	}
+
+	var fixes []ContrastFix
+	for _, name := range scheme.Base24Slots {
+		if from, ok := original[name]; ok && from != out.Palette.Resolved(name) {
+			fixes = append(fixes, ContrastFix{Slot: name, From: from, To: out.Palette.Resolved(name)})
+		}
+	}
+	return &out, fixes, report
+}
@@ -0,0 +1,34 @@
+package targets
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jaycee1285/base16changer/internal/scheme"
+)
+
+// CurrentSchemePath returns the file holding the last applied scheme,
+// with adjustments and overrides baked in
+func CurrentSchemePath() string {
+	return filepath.Join(StateDir(), "current.yaml")
+}
+
+// saveCurrent records s as the current scheme. It is written like a target
+// file, so undo and rollback restore the previous one.
+func saveCurrent(cfg *Config, s *scheme.Base16) error {
+	data, err := s.Marshal()
+	if err != nil {
+		return err
+	}
+	return writeFile(cfg, CurrentSchemePath(), string(data))
+}
+
+// CurrentScheme returns the scheme of the last apply
+func (c *Config) CurrentScheme() (*scheme.Base16, error) {
+	path := CurrentSchemePath()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, fmt.Errorf("no scheme has been applied yet (%s does not exist)", path)
+	}
+	return c.ParseScheme(path)
+}
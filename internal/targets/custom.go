@@ -0,0 +1,183 @@
+package targets
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/jaycee1285/base16changer/internal/scheme"
+	"github.com/jaycee1285/base16changer/internal/template"
+)
+
+// Write modes for custom targets
+const (
+	ModeFull       = "full"        // replace the whole file
+	ModeIniSection = "ini-section" // replace one [section], like fuzzel's [colors]
+	ModeMarkers    = "markers"     // replace the lines between two marker lines
+)
+
+// Default marker lines for ModeMarkers
+const (
+	DefaultBeginMarker = "# BEGIN base16changer"
+	DefaultEndMarker   = "# END base16changer"
+)
+
+// CustomTarget is a target declared in the config file: a user template
+// rendered into Output, optionally followed by a reload command or signal
+type CustomTarget struct {
+	TargetName string
+	Template   string // absolute, or relative to Config.TemplatesDir
+	Output     string
+	Mode       string // ModeFull, ModeIniSection or ModeMarkers
+	Section    string // INI section name for ModeIniSection
+	Begin, End string // marker lines for ModeMarkers
+	Command    string // reload command, run with sh -c
+	Signal     string // reload signal sent to Process with pkill
+	Process    string
+}
+
+func (t *CustomTarget) Name() string { return t.TargetName }
+
+// Detect always succeeds: the user asked for the target by declaring it
+func (t *CustomTarget) Detect(cfg *Config) bool { return true }
+
+func (t *CustomTarget) Render(cfg *Config, s *scheme.Base16) ([]File, error) {
+	path := t.Template
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(cfg.TemplatesDir, path)
+	}
+	rendered, err := template.Render(path, s.ToMap())
+	if err != nil {
+		return nil, err
+	}
+	if t.Mode == ModeFull {
+		return []File{{Path: t.Output, Content: rendered}}, nil
+	}
+
+	existing, err := os.ReadFile(t.Output)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	var content string
+	switch t.Mode {
+	case ModeIniSection:
+		section := withIniHeader(rendered, t.Section)
+		if len(existing) == 0 {
+			content = section
+		} else {
+			content = replaceIniSection(string(existing), t.Section, section)
+		}
+	case ModeMarkers:
+		if content, err = replaceMarkerBlock(string(existing), t.Begin, t.End, rendered); err != nil {
+			return nil, fmt.Errorf("%s: %w", t.Output, err)
+		}
+	}
+	return []File{{Path: t.Output, Content: content}}, nil
+}
+
+func (t *CustomTarget) Write(cfg *Config, files []File) error {
+	return writeFiles(cfg, files)
+}
+
+func (t *CustomTarget) Reload(cfg *Config) error {
+	switch {
+	case t.Command != "":
+		if cfg.DryRun {
+			logf(cfg, "  Would run: %s\n", t.Command)
+			return nil
+		}
+		return run("sh", "-c", t.Command)
+	case t.Signal != "":
+		sig := "-SIG" + t.Signal
+		if cfg.DryRun {
+			logf(cfg, "  Would run: pkill %s -x %s\n", sig, t.Process)
+			return nil
+		}
+		err := run("pkill", sig, "-x", t.Process)
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			// no matching process is running, so there is nothing to reload
+			return nil
+		}
+		return err
+	}
+	return ErrNoReload
+}
+
+// withIniHeader prepends "[section]" unless the rendered template already
+// starts its section with that header
+func withIniHeader(content, section string) string {
+	header := "[" + section + "]"
+	for _, line := range strings.Split(content, "\n") {
+		if strings.EqualFold(strings.TrimSpace(line), header) {
+			return content
+		}
+	}
+	return header + "\n" + content
+}
+
+// replaceMarkerBlock replaces the lines between begin and end with block,
+// keeping the marker lines. Without a begin marker the block is appended,
+// wrapped in the markers.
+func replaceMarkerBlock(content, begin, end, block string) (string, error) {
+	block = strings.TrimSuffix(block, "\n")
+	lines := strings.Split(content, "\n")
+	start := -1
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if start < 0 && trimmed == begin {
+			start = i
+			continue
+		}
+		if start >= 0 && trimmed == end {
+			out := append([]string{}, lines[:start+1]...)
+			out = append(out, block)
+			out = append(out, lines[i:]...)
+			return strings.Join(out, "\n"), nil
+		}
+	}
+	if start >= 0 {
+		return "", fmt.Errorf("%q on line %d has no matching %q", begin, start+1, end)
+	}
+
+	wrapped := begin + "\n" + block + "\n" + end + "\n"
+	if content == "" {
+		return wrapped, nil
+	}
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return content + "\n" + wrapped, nil
+}
+
+// Targets returns the registered targets followed by the config's custom
+// targets, in run order
+func (c *Config) Targets() []Target {
+	out := Registered()
+	for _, t := range c.Custom {
+		out = append(out, t)
+	}
+	return out
+}
+
+// Lookup finds a registered or custom target by name
+func (c *Config) Lookup(name string) (Target, bool) {
+	for _, t := range c.Targets() {
+		if t.Name() == name {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// TargetNames returns the names of all targets in run order
+func (c *Config) TargetNames() []string {
+	var names []string
+	for _, t := range c.Targets() {
+		names = append(names, t.Name())
+	}
+	return names
+}
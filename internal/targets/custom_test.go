@@ -0,0 +1,78 @@
+package targets
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReplaceMarkerBlock(t *testing.T) {
+	const begin, end = "# BEGIN base16changer", "# END base16changer"
+	cases := []struct {
+		name, content, block, want string
+	}{
+		{
+			"replaces existing block",
+			"a = 1\n# BEGIN base16changer\nold = 1\nold = 2\n# END base16changer\nb = 2\n",
+			"new = 1\n",
+			"a = 1\n# BEGIN base16changer\nnew = 1\n# END base16changer\nb = 2\n",
+		},
+		{
+			"keeps indented markers",
+			"  # BEGIN base16changer\nold\n  # END base16changer\n",
+			"new",
+			"  # BEGIN base16changer\nnew\n  # END base16changer\n",
+		},
+		{
+			"appends without markers",
+			"a = 1\n",
+			"new = 1\n",
+			"a = 1\n\n# BEGIN base16changer\nnew = 1\n# END base16changer\n",
+		},
+		{
+			"appends after a missing final newline",
+			"a = 1",
+			"new = 1",
+			"a = 1\n\n# BEGIN base16changer\nnew = 1\n# END base16changer\n",
+		},
+		{
+			"empty file",
+			"",
+			"new = 1\n",
+			"# BEGIN base16changer\nnew = 1\n# END base16changer\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := replaceMarkerBlock(c.content, begin, end, c.block)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.want {
+				t.Errorf("got\n%q\nwant\n%q", got, c.want)
+			}
+		})
+	}
+
+	_, err := replaceMarkerBlock("a\n# BEGIN base16changer\nold\n", begin, end, "new")
+	want := `"# BEGIN base16changer" on line 2 has no matching "# END base16changer"`
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("error %v, want %q", err, want)
+	}
+}
+
+func TestWithIniHeader(t *testing.T) {
+	cases := []struct {
+		name, content, want string
+	}{
+		{"adds header", "background=1d2021ff\n", "[colors]\nbackground=1d2021ff\n"},
+		{"keeps header", "[colors]\nbackground=1d2021ff\n", "[colors]\nbackground=1d2021ff\n"},
+		{"keeps header after comment", "# theme\n  [Colors]  \nbackground=1d2021ff\n", "# theme\n  [Colors]  \nbackground=1d2021ff\n"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := withIniHeader(c.content, "colors"); got != c.want {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
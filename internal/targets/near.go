@@ -0,0 +1,35 @@
+package targets
+
+import (
+	"sort"
+
+	"github.com/jaycee1285/base16changer/internal/scheme"
+)
+
+// NearMatch is one scheme ranked by a similarity search
+type NearMatch struct {
+	Name  string                `json:"name"`
+	Path  string                `json:"path"`
+	Score float64               `json:"score"` // mean ΔE00 over the searched colors
+	Slots []scheme.SlotDistance `json:"slots"`
+}
+
+// RankNear scores the named schemes by CIEDE2000 distance to colors and
+// returns them closest first. Schemes that fail to parse are left out.
+func (c *Config) RankNear(names []string, colors []scheme.NearColor) []NearMatch {
+	var out []NearMatch
+	for _, name := range names {
+		path, err := c.ResolveScheme(name)
+		if err != nil {
+			continue
+		}
+		s, err := c.ParseScheme(path)
+		if err != nil {
+			continue
+		}
+		score, slots := s.Near(colors)
+		out = append(out, NearMatch{Name: name, Path: path, Score: score, Slots: slots})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Score < out[j].Score })
+	return out
+}
@@ -0,0 +1,75 @@
+package targets
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jaycee1285/base16changer/internal/template"
+)
+
+// TemplatesDir returns $XDG_CONFIG_HOME/base16changer/templates, where
+// same-named files override the built-in templates
+func TemplatesDir() string {
+	return filepath.Join(ConfigDir(), "templates")
+}
+
+// BuiltinTemplates lists the file names of the built-in templates
+func BuiltinTemplates() []string {
+	names := make([]string, len(builtinTemplates))
+	for i, t := range builtinTemplates {
+		names[i] = t.name
+	}
+	return names
+}
+
+// builtinTemplate returns the embedded content of a built-in template
+func builtinTemplate(name string) (string, bool) {
+	for _, t := range builtinTemplates {
+		if t.name == name {
+			return t.content, true
+		}
+	}
+	return "", false
+}
+
+// TemplateOverride returns the path of the user's copy of a built-in
+// template, or "" when the embedded one is used
+func (c *Config) TemplateOverride(name string) string {
+	path := filepath.Join(c.TemplatesDir, name)
+	if fi, err := os.Stat(path); err == nil && !fi.IsDir() {
+		return path
+	}
+	return ""
+}
+
+// renderTemplate renders a built-in template, preferring the user's copy
+// in TemplatesDir. Overrides can use partials from the same directory.
+func renderTemplate(cfg *Config, name string, data map[string]string) (string, error) {
+	if path := cfg.TemplateOverride(name); path != "" {
+		return template.Render(path, data)
+	}
+	content, ok := builtinTemplate(name)
+	if !ok {
+		return "", fmt.Errorf("no built-in template %q", name)
+	}
+	return template.RenderString(content, data)
+}
+
+// DumpTemplate writes a built-in template into TemplatesDir for editing
+// and returns its path. An existing file is only replaced when force is
+// set; dry runs print the file instead.
+func (c *Config) DumpTemplate(name string, force bool) (string, error) {
+	content, ok := builtinTemplate(name)
+	if !ok {
+		return "", fmt.Errorf("no built-in template %q", name)
+	}
+	path := filepath.Join(c.TemplatesDir, name)
+	if _, err := os.Stat(path); err == nil && !force {
+		return "", fmt.Errorf("%s already exists (use --force to replace it)", path)
+	}
+	if err := writeFile(c, path, content); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,36 @@
+package targets
+
+import (
+	"os"
+
+	"github.com/jaycee1285/base16changer/internal/diff"
+)
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+// previewWrite prints what writing content to path would change
+func previewWrite(cfg *Config, path, content string) {
+	old, err := os.ReadFile(path)
+	if err != nil {
+		logf(cfg, "  Would create: %s\n", path)
+		logf(cfg, "%s", diff.Unified("/dev/null", path, "", content, diffContext, cfg.Color))
+		return
+	}
+	if string(old) == content {
+		logf(cfg, "  Unchanged: %s\n", path)
+		return
+	}
+	logf(cfg, "  Would modify: %s\n", path)
+	logf(cfg, "%s", diff.Unified(path, path, string(old), content, diffContext, cfg.Color))
+}
+
+// previewRemove prints what deleting path would remove
+func previewRemove(cfg *Config, path string) {
+	old, err := os.ReadFile(path)
+	if err != nil {
+		return // nothing to delete
+	}
+	logf(cfg, "  Would delete: %s\n", path)
+	logf(cfg, "%s", diff.Unified(path, "/dev/null", string(old), "", diffContext, cfg.Color))
+}
@@ -0,0 +1,91 @@
+package targets
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Status is the outcome of a single target or reload
+type Status string
+
+const (
+	StatusOK      Status = "ok"
+	StatusSkipped Status = "skipped"
+	StatusFailed  Status = "failed"
+	StatusNone    Status = "none" // target has no reload step
+)
+
+// Result records what happened to one target during Apply
+type Result struct {
+	Target      string   `json:"target"`
+	Status      Status   `json:"status"`
+	Error       string   `json:"error,omitempty"`
+	Files       []string `json:"files,omitempty"`
+	Removed     []string `json:"removed,omitempty"`
+	Reload      Status   `json:"reload,omitempty"`
+	ReloadError string   `json:"reload_error,omitempty"`
+}
+
+// Report is the outcome of an Apply run
+type Report struct {
+	Scheme     string   `json:"scheme"`
+	DryRun     bool     `json:"dry_run"`
+	Generation int      `json:"generation,omitempty"` // backup generation saved by the run
+	OutputDir  string   `json:"output_dir,omitempty"` // set when rendering into a staging tree
+	Adjust     []string `json:"adjust,omitempty"`     // palette transforms applied before rendering
+	Results    []Result `json:"results"`
+}
+
+// Failed reports whether any target or reload failed
+func (r *Report) Failed() bool {
+	return len(r.Failures()) > 0
+}
+
+// Failures returns the results whose write or reload failed
+func (r *Report) Failures() []Result {
+	var out []Result
+	for _, res := range r.Results {
+		if res.Status == StatusFailed || res.Reload == StatusFailed {
+			out = append(out, res)
+		}
+	}
+	return out
+}
+
+// Count returns how many targets ended with the given status
+func (r *Report) Count(s Status) int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Status == s {
+			n++
+		}
+	}
+	return n
+}
+
+// Summary returns a one-line description such as "9 ok, 1 failed, 2 skipped"
+func (r *Report) Summary() string {
+	parts := []string{fmt.Sprintf("%d ok", r.Count(StatusOK))}
+	if n := r.Count(StatusFailed); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", n))
+	}
+	if n := r.Count(StatusSkipped); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d skipped", n))
+	}
+	reloadFailed := 0
+	for _, res := range r.Results {
+		if res.Reload == StatusFailed {
+			reloadFailed++
+		}
+	}
+	if reloadFailed > 0 {
+		parts = append(parts, fmt.Sprintf("%d reload(s) failed", reloadFailed))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// JSON returns the report as indented JSON
+func (r *Report) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
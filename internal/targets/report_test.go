@@ -0,0 +1,57 @@
+package targets
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/jaycee1285/base16changer/internal/scheme"
+)
+
+func TestApplyNoisyReloadKeepsStdoutClean(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_STATE_HOME", home)
+
+	Register(&builtin{name: "test-noisy", reload: func(*Config) error {
+		return run("sh", "-c", "echo reloaded")
+	}})
+	cfg := DefaultConfig()
+	cfg.Quiet = true
+	cfg.Only = []string{"test-noisy"}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	null, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer null.Close()
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = w, null
+	report, err := Apply(cfg, &scheme.Base16{Name: "Noisy"})
+	os.Stdout, os.Stderr = stdout, stderr
+	w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, _ := io.ReadAll(r)
+	if len(out) > 0 {
+		t.Errorf("Apply wrote to stdout: %q", out)
+	}
+
+	data, err := report.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("report is not valid JSON: %v\n%s", err, data)
+	}
+	if len(decoded.Results) != 1 || decoded.Results[0].Reload != StatusOK {
+		t.Errorf("results = %+v, want one reloaded target", decoded.Results)
+	}
+}
@@ -0,0 +1,42 @@
+package targets
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jaycee1285/base16changer/internal/scheme"
+)
+
+// UserSchemesDir returns where generated schemes are saved: the first
+// configured scheme directory (~/.local/share/themes by default)
+func (c *Config) UserSchemesDir() string {
+	if len(c.SchemeDirs) > 0 {
+		return c.SchemeDirs[0]
+	}
+	return SchemesDirs()[0]
+}
+
+// ErrSchemeExists is returned by SaveScheme when the file exists and force
+// is not set
+var ErrSchemeExists = errors.New("already exists")
+
+// SaveScheme writes s as <name>.yaml into the user scheme directory and
+// returns its path. An existing file is only replaced when force is set;
+// dry runs print the file instead.
+func (c *Config) SaveScheme(s *scheme.Base16, name string, force bool) (string, error) {
+	path := filepath.Join(c.UserSchemesDir(), name+".yaml")
+	if _, err := os.Stat(path); err == nil && !force {
+		return "", fmt.Errorf("%s %w (use --force to replace it)", path, ErrSchemeExists)
+	}
+
+	data, err := s.Marshal()
+	if err != nil {
+		return "", err
+	}
+	if err := writeFile(c, path, string(data)); err != nil {
+		return "", err
+	}
+	return path, nil
+}
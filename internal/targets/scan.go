@@ -6,9 +6,11 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+
+	"github.com/jaycee1285/base16changer/internal/scheme"
 )
 
-// SchemesDirs returns directories to scan for base16 scheme YAML files
+// SchemesDirs returns directories to scan for base16/base24 scheme YAML files
 func SchemesDirs() []string {
 	home, _ := os.UserHomeDir()
 	return []string{
@@ -17,46 +19,39 @@ func SchemesDirs() []string {
 	}
 }
 
-// ScanSchemesDir returns available base16 scheme names from a single directory
+// schemeSubdirs are also scanned inside each schemes directory, matching
+// the tinted-theming schemes repository layout. base24 comes first because
+// it is a superset of base16 when a scheme exists in both.
+var schemeSubdirs = []string{"base24", "base16"}
+
+// ScanSchemesDir returns available base16/base24 scheme names from a single
+// directory and its base16/ and base24/ subdirectories
 func ScanSchemesDir(dir string) ([]string, error) {
-	entries, err := os.ReadDir(dir)
-	if err != nil {
+	if _, err := os.ReadDir(dir); err != nil {
 		return nil, err
 	}
-
-	var schemes []string
-	for _, e := range entries {
-		if e.IsDir() {
-			continue
-		}
-		name := e.Name()
-		if strings.HasSuffix(name, ".yaml") {
-			schemes = append(schemes, strings.TrimSuffix(name, ".yaml"))
-		} else if strings.HasSuffix(name, ".yml") {
-			schemes = append(schemes, strings.TrimSuffix(name, ".yml"))
-		}
-	}
-	sort.Strings(schemes)
-	return schemes, nil
+	return ScanAllSchemes([]string{dir}), nil
 }
 
-// ScanAllSchemes returns schemes from all SchemesDirs, deduped
-func ScanAllSchemes() []string {
+// ScanAllSchemes returns schemes from all dirs, deduped
+func ScanAllSchemes(dirs []string) []string {
 	set := make(map[string]struct{})
-	for _, dir := range SchemesDirs() {
-		entries, err := os.ReadDir(dir)
-		if err != nil {
-			continue
-		}
-		for _, e := range entries {
-			if e.IsDir() {
+	for _, dir := range dirs {
+		for _, sub := range append([]string{""}, schemeSubdirs...) {
+			entries, err := os.ReadDir(filepath.Join(dir, sub))
+			if err != nil {
 				continue
 			}
-			name := e.Name()
-			if strings.HasSuffix(name, ".yaml") {
-				set[strings.TrimSuffix(name, ".yaml")] = struct{}{}
-			} else if strings.HasSuffix(name, ".yml") {
-				set[strings.TrimSuffix(name, ".yml")] = struct{}{}
+			for _, e := range entries {
+				if e.IsDir() {
+					continue
+				}
+				name := e.Name()
+				if strings.HasSuffix(name, ".yaml") {
+					set[strings.TrimSuffix(name, ".yaml")] = struct{}{}
+				} else if strings.HasSuffix(name, ".yml") {
+					set[strings.TrimSuffix(name, ".yml")] = struct{}{}
+				}
 			}
 		}
 	}
@@ -69,19 +64,82 @@ func ScanAllSchemes() []string {
 	return schemes
 }
 
-// FindScheme searches SchemesDirs for a scheme and returns its full path
-func FindScheme(name string) (string, error) {
-	for _, dir := range SchemesDirs() {
-		for _, ext := range []string{".yaml", ".yml"} {
-			path := filepath.Join(dir, name+ext)
-			if _, err := os.Stat(path); err == nil {
-				return path, nil
+// FindScheme searches dirs (and their base24/ and base16/ subdirectories)
+// for a scheme and returns its full path. A name like "base16/nord" picks
+// a specific subdirectory.
+func FindScheme(dirs []string, name string) (string, error) {
+	for _, dir := range dirs {
+		for _, sub := range append([]string{""}, schemeSubdirs...) {
+			for _, ext := range []string{".yaml", ".yml"} {
+				path := filepath.Join(dir, sub, name+ext)
+				if _, err := os.Stat(path); err == nil {
+					return path, nil
+				}
 			}
 		}
 	}
 	return "", fmt.Errorf("scheme not found: %s", name)
 }
 
+// SchemeFiles expands files and directories into the scheme YAML files
+// they contain. Directories are walked recursively, skipping hidden ones
+// such as .git.
+func SchemeFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, p := range paths {
+		err := filepath.WalkDir(p, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path != p && strings.HasPrefix(d.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if path == p || strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// ScanSchemes returns the schemes visible to cfg: only cfg.SchemesDir when
+// set (CLI override), otherwise every directory in cfg.SchemeDirs
+func (c *Config) ScanSchemes() []string {
+	if c.SchemesDir != "" {
+		schemes, _ := ScanSchemesDir(c.SchemesDir)
+		return schemes
+	}
+	return ScanAllSchemes(c.SchemeDirs)
+}
+
+// ResolveScheme returns the path of a scheme by name, honoring cfg.SchemesDir
+func (c *Config) ResolveScheme(name string) (string, error) {
+	if c.SchemesDir != "" {
+		path, err := FindScheme([]string{c.SchemesDir}, name)
+		if err != nil {
+			return "", fmt.Errorf("%w (searched %s)", err, c.SchemesDir)
+		}
+		return path, nil
+	}
+	path, err := FindScheme(c.SchemeDirs, name)
+	if err != nil {
+		return "", fmt.Errorf("%w (searched %s)", err, strings.Join(c.SchemeDirs, ", "))
+	}
+	return path, nil
+}
+
+// ParseScheme parses a scheme file, looking up the schemes it extends in
+// the configured scheme dirs when they are not next to it
+func (c *Config) ParseScheme(path string) (*scheme.Base16, error) {
+	return scheme.ParseResolve(path, c.ResolveScheme)
+}
 
 // dirEntryIsDir returns true for real directories AND symlinks that point to directories.
 // NixOS commonly exposes themes/icons under /run/current-system/sw as symlink entries.
@@ -121,10 +179,10 @@ func WallpaperDir() string {
 	return filepath.Join(home, "Pictures/walls")
 }
 
-// ScanIconThemes returns available icon themes
-func ScanIconThemes() []string {
+// ScanIconThemes returns available icon themes from dirs
+func ScanIconThemes(dirs []string) []string {
 	set := map[string]struct{}{}
-	for _, dir := range IconDirs() {
+	for _, dir := range dirs {
 		entries, err := os.ReadDir(dir)
 		if err != nil {
 			continue
@@ -151,9 +209,8 @@ func ScanIconThemes() []string {
 	return out
 }
 
-// ScanWallpapers returns available wallpapers
-func ScanWallpapers() []string {
-	dir := WallpaperDir()
+// ScanWallpapers returns available wallpapers in dir
+func ScanWallpapers(dir string) []string {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return []string{}
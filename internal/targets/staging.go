@@ -0,0 +1,64 @@
+package targets
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ManifestName is the file written to the root of an output directory
+const ManifestName = "manifest.json"
+
+// Manifest lists every file rendered into an output directory
+type Manifest struct {
+	Scheme    string          `json:"scheme"`
+	Generated time.Time       `json:"generated"`
+	Files     []ManifestEntry `json:"files"`
+}
+
+// ManifestEntry maps a rendered file back to where Apply would normally write it
+type ManifestEntry struct {
+	Target      string `json:"target"`
+	Path        string `json:"path,omitempty"` // relative to the output directory
+	Destination string `json:"destination"`
+	Remove      bool   `json:"remove,omitempty"` // destination would be deleted
+}
+
+// stagePath maps a destination into cfg.OutputDir. Paths under $HOME keep
+// their home-relative layout; anything else keeps its absolute layout.
+func stagePath(cfg *Config, dest string) string {
+	home, _ := os.UserHomeDir()
+	rel, err := filepath.Rel(home, dest)
+	if err == nil && home != "" && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.Join(cfg.OutputDir, rel)
+	}
+	return filepath.Join(cfg.OutputDir, strings.TrimPrefix(dest, string(filepath.Separator)))
+}
+
+// stageFiles redirects rendered files into cfg.OutputDir. Removals are only
+// recorded, since the staging tree never deletes anything.
+func stageFiles(cfg *Config, target string, files []File) ([]File, []ManifestEntry) {
+	var staged []File
+	var entries []ManifestEntry
+	for _, f := range files {
+		entry := ManifestEntry{Target: target, Destination: f.Path, Remove: f.Remove}
+		if !f.Remove {
+			path := stagePath(cfg, f.Path)
+			entry.Path, _ = filepath.Rel(cfg.OutputDir, path)
+			staged = append(staged, File{Path: path, Content: f.Content})
+		}
+		entries = append(entries, entry)
+	}
+	return staged, entries
+}
+
+// writeManifest saves m to the root of cfg.OutputDir
+func writeManifest(cfg *Config, m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(cfg, filepath.Join(cfg.OutputDir, ManifestName), string(data)+"\n")
+}
@@ -0,0 +1,175 @@
+package targets
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jaycee1285/base16changer/internal/scheme"
+)
+
+// File is a rendered output destined for disk
+type File struct {
+	Path    string
+	Content string
+	Remove  bool // delete Path instead of writing Content
+}
+
+// Target is a single themable application or desktop component
+type Target interface {
+	// Name identifies the target in --only/--skip and the config file
+	Name() string
+	// Detect reports whether the target applies to this system and config
+	Detect(cfg *Config) bool
+	// Render produces the files the target would write for a scheme
+	Render(cfg *Config, s *scheme.Base16) ([]File, error)
+	// Write puts rendered files (and any non-file side effects) in place
+	Write(cfg *Config, files []File) error
+	// Reload asks the running application to pick up the new theme.
+	// Targets without a reload step return ErrNoReload.
+	Reload(cfg *Config) error
+}
+
+// ErrNoReload is returned by Reload for targets that have nothing to reload
+var ErrNoReload = errors.New("no reload step")
+
+var registry []Target
+
+// Register adds a target to the registry. Targets run in registration order.
+func Register(t Target) {
+	if _, ok := Lookup(t.Name()); ok {
+		panic("targets: duplicate target " + t.Name())
+	}
+	registry = append(registry, t)
+}
+
+// Registered returns all registered targets in run order
+func Registered() []Target {
+	return append([]Target(nil), registry...)
+}
+
+// Lookup finds a registered target by name
+func Lookup(name string) (Target, bool) {
+	for _, t := range registry {
+		if t.Name() == name {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// Names returns the names of all registered targets in run order
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for _, t := range registry {
+		names = append(names, t.Name())
+	}
+	return names
+}
+
+// Selected returns the registered and custom targets enabled by cfg.Only
+// and cfg.Skip
+func Selected(cfg *Config) ([]Target, error) {
+	only, err := nameSet(cfg, cfg.Only)
+	if err != nil {
+		return nil, fmt.Errorf("only: %w", err)
+	}
+	skip, err := nameSet(cfg, cfg.Skip)
+	if err != nil {
+		return nil, fmt.Errorf("skip: %w", err)
+	}
+
+	var out []Target
+	for _, t := range cfg.Targets() {
+		if len(only) > 0 && !only[t.Name()] {
+			continue
+		}
+		if skip[t.Name()] {
+			continue
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+// nameSet validates target names against the registry and cfg's custom
+// targets
+func nameSet(cfg *Config, names []string) (map[string]bool, error) {
+	set := make(map[string]bool, len(names))
+	var unknown []string
+	for _, n := range names {
+		n = strings.TrimSpace(n)
+		if n == "" {
+			continue
+		}
+		if _, ok := cfg.Lookup(n); !ok {
+			unknown = append(unknown, n)
+			continue
+		}
+		set[n] = true
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown target(s) %s (available: %s)",
+			strings.Join(unknown, ", "), strings.Join(cfg.TargetNames(), ", "))
+	}
+	return set, nil
+}
+
+// builtin implements Target with plain functions. A nil detect means the
+// target always applies, a nil write writes the rendered files and a nil
+// reload reports ErrNoReload.
+type builtin struct {
+	name   string
+	detect func(cfg *Config) bool
+	render func(cfg *Config, s *scheme.Base16) ([]File, error)
+	write  func(cfg *Config, files []File) error
+	reload func(cfg *Config) error
+}
+
+func (b *builtin) Name() string { return b.name }
+
+func (b *builtin) Detect(cfg *Config) bool {
+	if b.detect == nil {
+		return true
+	}
+	return b.detect(cfg)
+}
+
+func (b *builtin) Render(cfg *Config, s *scheme.Base16) ([]File, error) {
+	if b.render == nil {
+		return nil, nil
+	}
+	return b.render(cfg, s)
+}
+
+func (b *builtin) Write(cfg *Config, files []File) error {
+	if b.write == nil {
+		return writeFiles(cfg, files)
+	}
+	return b.write(cfg, files)
+}
+
+func (b *builtin) Reload(cfg *Config) error {
+	if b.reload == nil {
+		return ErrNoReload
+	}
+	return b.reload(cfg)
+}
+
+// writeFiles writes or removes each rendered file
+func writeFiles(cfg *Config, files []File) error {
+	for _, f := range files {
+		if f.Remove {
+			if err := removeFile(cfg, f.Path); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := writeFile(cfg, f.Path, f.Content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package targets
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -9,25 +10,29 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jaycee1285/base16changer/internal/convert"
 	"github.com/jaycee1285/base16changer/internal/scheme"
-	"github.com/jaycee1285/base16changer/internal/template"
 )
 
 // Config holds paths and settings for theme application
 type Config struct {
 	// Scheme directories
-	SchemesDir string // Path to base16 schemes (YAML files)
+	SchemesDir string   // Path to base16 schemes (YAML files), CLI override
+	SchemeDirs []string // Directories searched for schemes when SchemesDir is unset
+	IconDirs   []string // Directories searched for icon themes
 
 	// Target config paths
-	KittyThemeConf string // ~/.config/kitty/current-theme.conf
-	FuzzelIni      string // ~/.config/fuzzel/fuzzel.ini
-	Gtk2RC         string // ~/.themes/Base16/gtk-2.0/gtkrc
-	Gtk3CSS        string // ~/.themes/Base16/gtk-3.0/gtk.css
-	Gtk4CSS        string // ~/.config/gtk-4.0/gtk.css (libadwaita)
-	Gtk4ThemeCSS   string // ~/.themes/Base16/gtk-4.0/gtk.css
-	IndexTheme     string // ~/.themes/Base16/index.theme
-	OpenboxThemerc string // ~/.themes/Base16/openbox-3/themerc
-	LabwcRcXml     string // ~/.config/labwc/rc.xml
+	KittyThemeConf  string   // ~/.config/kitty/current-theme.conf
+	FuzzelIni       string   // ~/.config/fuzzel/fuzzel.ini
+	Gtk2RC          string   // ~/.themes/Base16/gtk-2.0/gtkrc
+	Gtk3CSS         string   // ~/.themes/Base16/gtk-3.0/gtk.css
+	Gtk3UserCSS     string   // ~/.config/gtk-3.0/gtk.css (removed, overrides theme colors)
+	Gtk4CSS         string   // ~/.config/gtk-4.0/gtk.css (libadwaita)
+	Gtk4ThemeCSS    string   // ~/.themes/Base16/gtk-4.0/gtk.css
+	GtkSettingsInis []string // ~/.config/gtk-{3,4}.0/settings.ini
+	IndexTheme      string   // ~/.themes/Base16/index.theme
+	OpenboxThemerc  string   // ~/.themes/Base16/openbox-3/themerc
+	LabwcRcXml      string   // ~/.config/labwc/rc.xml
 
 	// Openbox theme name (written to rc.xml)
 	OpenboxThemeName string
@@ -42,14 +47,48 @@ type Config struct {
 	Wallpaper    string
 	WallpaperDir string
 
-	// Dry run mode - print what would be done
+	// Adjust transforms the scheme's palette before it is rendered
+	Adjust []scheme.Adjustment
+
+	// Dry run mode - print what would be done, with a diff per file
 	DryRun bool
 
+	// Color enables ANSI colors in dry-run diffs
+	Color bool
+
+	// OutputDir renders every target into this directory instead of its
+	// real destination, skipping reloads and non-file side effects
+	OutputDir string
+
 	// Quiet mode - suppress stdout logging (useful for TUI)
 	Quiet bool
 
+	// TemplatesDir holds user copies of the built-in templates, which
+	// replace the embedded ones
+	TemplatesDir string
+
 	// Ferritebar config to touch after apply
 	FerritebarConfig string
+
+	// Design-token exports written on every apply (none by default)
+	TokensDir    string           // ~/.local/share/base16changer/tokens
+	TokenFormats []convert.Format // css, scss, gpl, tokens, nix
+
+	// Target selection by name (empty Only means all registered targets)
+	Only []string
+	Skip []string
+
+	// Custom targets from the config file, run after the built-in ones
+	Custom []*CustomTarget
+
+	// Backups: every run snapshots the files it touches into a generation
+	// under StateDir unless NoBackup is set. KeepGenerations 0 keeps all.
+	NoBackup        bool
+	KeepGenerations int
+
+	gen *Generation // generation being recorded by the current Apply
+
+	gtkReloaded bool // the GTK targets share one reload per run
 }
 
 // DefaultConfig returns config with standard paths
@@ -57,159 +96,207 @@ func DefaultConfig() *Config {
 	home, _ := os.UserHomeDir()
 	return &Config{
 		// SchemesDir is used for CLI --schemes-dir override only
-		// ScanSchemesDirs() returns the actual search paths
-		KittyThemeConf:   filepath.Join(home, ".config/kitty/current-theme.conf"),
-		FuzzelIni:        filepath.Join(home, ".config/fuzzel/fuzzel.ini"),
-		Gtk2RC:           filepath.Join(home, ".themes/Base16/gtk-2.0/gtkrc"),
-		Gtk3CSS:          filepath.Join(home, ".themes/Base16/gtk-3.0/gtk.css"),
-		Gtk4CSS:          filepath.Join(home, ".config/gtk-4.0/gtk.css"),
-		Gtk4ThemeCSS:     filepath.Join(home, ".themes/Base16/gtk-4.0/gtk.css"),
+		// SchemeDirs holds the actual search paths
+		SchemeDirs:     SchemesDirs(),
+		IconDirs:       IconDirs(),
+		KittyThemeConf: filepath.Join(home, ".config/kitty/current-theme.conf"),
+		FuzzelIni:      filepath.Join(home, ".config/fuzzel/fuzzel.ini"),
+		Gtk2RC:         filepath.Join(home, ".themes/Base16/gtk-2.0/gtkrc"),
+		Gtk3CSS:        filepath.Join(home, ".themes/Base16/gtk-3.0/gtk.css"),
+		Gtk3UserCSS:    filepath.Join(home, ".config/gtk-3.0/gtk.css"),
+		Gtk4CSS:        filepath.Join(home, ".config/gtk-4.0/gtk.css"),
+		Gtk4ThemeCSS:   filepath.Join(home, ".themes/Base16/gtk-4.0/gtk.css"),
+		GtkSettingsInis: []string{
+			filepath.Join(home, ".config/gtk-3.0/settings.ini"),
+			filepath.Join(home, ".config/gtk-4.0/settings.ini"),
+		},
 		IndexTheme:       filepath.Join(home, ".themes/Base16/index.theme"),
 		OpenboxThemerc:   filepath.Join(home, ".themes/Base16/openbox-3/themerc"),
 		LabwcRcXml:       filepath.Join(home, ".config/labwc/rc.xml"),
 		OpenboxThemeName: "Base16",
 		GtkThemeName:     "Base16",
-		WallpaperDir:     filepath.Join(home, "Pictures/walls"),
+		WallpaperDir:     WallpaperDir(),
 		DryRun:           false,
 		Quiet:            false,
 		FerritebarConfig: filepath.Join(home, ".config/ferritebar/config.toml"),
+		TemplatesDir:     TemplatesDir(),
+		TokensDir:        filepath.Join(home, ".local/share/base16changer/tokens"),
+		KeepGenerations:  DefaultKeepGenerations,
 	}
 }
 
-// Apply applies a base16 scheme to all targets
-func Apply(cfg *Config, s *scheme.Base16) error {
-	logf(cfg, "Applying scheme: %s\n", s.Name)
+func init() {
+	Register(&builtin{name: "kitty", render: renderKitty, reload: reloadKitty})
+	Register(&builtin{name: "fuzzel", render: renderFuzzel})
+	Register(&builtin{name: "gtk4", render: renderGtk4, reload: reloadGtk})
+	Register(&builtin{name: "gtk2", render: renderGtk2, reload: reloadGtk})
+	Register(&builtin{name: "gtk3", render: renderGtk3, reload: reloadGtk})
+	Register(&builtin{name: "index-theme", render: renderIndexTheme})
+	Register(&builtin{name: "gtk-settings", render: renderGtkSettingsIni, reload: reloadGtk})
+	Register(&builtin{name: "openbox", render: renderOpenbox})
+	Register(&builtin{name: "labwc", detect: detectLabwc, render: renderLabwcRcXml, reload: reloadLabwc})
+	Register(&builtin{name: "icons", detect: detectIconTheme, write: applyIconTheme})
+	Register(&builtin{name: "wallpaper", detect: detectWallpaper, write: applyWallpaper})
+	Register(&builtin{name: "ferritebar", reload: touchFerritebarConfig})
+	Register(&builtin{name: "tokens", detect: detectTokens, render: renderTokens})
+}
 
-	// 1. Kitty
-	if err := applyKitty(cfg, s); err != nil {
-		logf(cfg, "  [WARN] kitty: %v\n", err)
-	} else {
-		logln(cfg, "  [OK] kitty")
+// Apply applies a base16 scheme to the selected targets and reports what
+// happened to each. The error is only set when Apply could not run at all;
+// per-target failures are recorded in the report.
+func Apply(cfg *Config, s *scheme.Base16) (*Report, error) {
+	selected, err := Selected(cfg)
+	if err != nil {
+		return nil, err
 	}
 
-	// 2. Fuzzel
-	if err := applyFuzzel(cfg, s); err != nil {
-		logf(cfg, "  [WARN] fuzzel: %v\n", err)
-	} else {
-		logln(cfg, "  [OK] fuzzel")
+	if len(cfg.Adjust) > 0 {
+		s = s.Adjust(cfg.Adjust)
 	}
 
-	// 3. GTK-4
-	if err := applyGtk4(cfg, s); err != nil {
-		logf(cfg, "  [WARN] gtk-4: %v\n", err)
-	} else {
-		logln(cfg, "  [OK] gtk-4")
-	}
+	staging := cfg.OutputDir != ""
+	manifest := &Manifest{Scheme: s.Name, Generated: time.Now()}
 
-	// 4. GTK-2
-	if err := applyGtk2(cfg, s); err != nil {
-		logf(cfg, "  [WARN] gtk-2: %v\n", err)
-	} else {
-		logln(cfg, "  [OK] gtk-2")
+	if !cfg.DryRun && !cfg.NoBackup && !staging {
+		gen, err := beginGeneration(s.Name)
+		if err != nil {
+			return nil, err
+		}
+		cfg.gen = gen
+		defer func() { cfg.gen = nil }()
 	}
 
-	// 5. GTK-3 (theme directory)
-	if err := applyGtk3(cfg, s); err != nil {
-		logf(cfg, "  [WARN] gtk-3: %v\n", err)
-	} else {
-		logln(cfg, "  [OK] gtk-3")
+	logf(cfg, "Applying scheme: %s\n", s.Name)
+	if len(cfg.Adjust) > 0 {
+		logf(cfg, "  Adjusted: %s\n", scheme.FormatAdjustments(cfg.Adjust))
 	}
-	// Clean up old user CSS that would override theme colors
-	cleanupOldGtkCSS(cfg)
 
-	// 4b. Theme index.theme
-	if err := applyIndexTheme(cfg); err != nil {
-		logf(cfg, "  [WARN] index.theme: %v\n", err)
-	} else {
-		logln(cfg, "  [OK] index.theme")
+	report := &Report{Scheme: s.Name, DryRun: cfg.DryRun, OutputDir: cfg.OutputDir}
+	for _, a := range cfg.Adjust {
+		report.Adjust = append(report.Adjust, a.String())
 	}
-
-	// 4c. GTK settings.ini (set theme name)
-	home, _ := os.UserHomeDir()
-	for _, iniPath := range []string{
-		filepath.Join(home, ".config/gtk-3.0/settings.ini"),
-		filepath.Join(home, ".config/gtk-4.0/settings.ini"),
-	} {
-		if err := updateGtkSettingsIni(cfg, iniPath); err != nil {
-			logf(cfg, "  [WARN] %s: %v\n", iniPath, err)
+	var applied []int
+	for _, t := range selected {
+		res := Result{Target: t.Name(), Status: StatusSkipped}
+		if !t.Detect(cfg) {
+			logf(cfg, "  [SKIP] %s\n", t.Name())
+			report.Results = append(report.Results, res)
+			continue
 		}
+		if cfg.gen != nil {
+			cfg.gen.Targets = append(cfg.gen.Targets, t.Name())
+		}
+		files, err := t.Render(cfg, s)
+		if err == nil && staging {
+			if len(files) == 0 {
+				// Only side effects (dconf, swww, touch), which staging skips
+				logf(cfg, "  [SKIP] %s\n", t.Name())
+				report.Results = append(report.Results, res)
+				continue
+			}
+			var entries []ManifestEntry
+			files, entries = stageFiles(cfg, t.Name(), files)
+			manifest.Files = append(manifest.Files, entries...)
+			err = writeFiles(cfg, files)
+		} else if err == nil {
+			err = t.Write(cfg, files)
+		}
+		if err != nil {
+			logf(cfg, "  [WARN] %s: %v\n", t.Name(), err)
+			res.Status = StatusFailed
+			res.Error = err.Error()
+			report.Results = append(report.Results, res)
+			continue
+		}
+		logf(cfg, "  [OK] %s\n", t.Name())
+		res.Status = StatusOK
+		for _, f := range files {
+			if f.Remove {
+				res.Removed = append(res.Removed, f.Path)
+			} else {
+				res.Files = append(res.Files, f.Path)
+			}
+		}
+		report.Results = append(report.Results, res)
+		applied = append(applied, len(report.Results)-1)
 	}
 
-	// 5. LabWC/Openbox themerc
-	if err := applyOpenbox(cfg, s); err != nil {
-		logf(cfg, "  [WARN] openbox: %v\n", err)
-	} else {
-		logln(cfg, "  [OK] openbox")
-	}
-
-	// 6. LabWC rc.xml (set theme name and icon theme)
-	if err := updateLabwcRcXml(cfg); err != nil {
-		logf(cfg, "  [WARN] labwc rc.xml: %v\n", err)
-	} else {
-		logln(cfg, "  [OK] labwc rc.xml")
+	if len(applied) > 0 && !cfg.DryRun && !staging {
+		if err := saveCurrent(cfg, s); err != nil {
+			logf(cfg, "  [WARN] save current scheme: %v\n", err)
+		}
 	}
 
-	// 7. Icon theme (if specified)
-	if cfg.IconTheme != "" {
-		if err := applyIconTheme(cfg); err != nil {
-			logf(cfg, "  [WARN] icon theme: %v\n", err)
-		} else {
-			logln(cfg, "  [OK] icon theme")
+	if cfg.gen != nil {
+		if err := cfg.gen.commit(cfg.KeepGenerations); err != nil {
+			logf(cfg, "  [WARN] save generation: %v\n", err)
+		} else if !cfg.gen.empty() {
+			report.Generation = cfg.gen.ID
+			logf(cfg, "  Saved generation %d\n", cfg.gen.ID)
 		}
 	}
 
-	// 8. Wallpaper (if specified)
-	if cfg.Wallpaper != "" {
-		if err := applyWallpaper(cfg); err != nil {
-			logf(cfg, "  [WARN] wallpaper: %v\n", err)
-		} else {
-			logln(cfg, "  [OK] wallpaper")
+	if staging {
+		if err := writeManifest(cfg, manifest); err != nil {
+			return report, fmt.Errorf("write manifest: %w", err)
 		}
+		logf(cfg, "  Rendered into %s (see %s)\n", cfg.OutputDir, ManifestName)
+		return report, nil
 	}
 
-	// 9. Trigger reloads
 	logln(cfg, "\nTriggering reloads...")
-	triggerReloads(cfg)
-
-	// 10. Touch ferritebar config (final step)
-	if err := touchFerritebarConfig(cfg); err != nil {
-		logf(cfg, "  [WARN] ferritebar config: %v\n", err)
-	} else {
-		logln(cfg, "  [OK] ferritebar config")
+	cfg.gtkReloaded = false
+	for _, i := range applied {
+		res := &report.Results[i]
+		t, _ := cfg.Lookup(res.Target)
+		reload(cfg, t, res)
 	}
 
-	return nil
+	return report, nil
 }
 
-func applyKitty(cfg *Config, s *scheme.Base16) error {
-	content, err := template.RenderString(kittyTemplate, s.ToMap())
-	if err != nil {
-		return err
+// reload runs t's reload step and records the outcome in res
+func reload(cfg *Config, t Target, res *Result) {
+	err := t.Reload(cfg)
+	switch {
+	case errors.Is(err, ErrNoReload):
+		res.Reload = StatusNone
+	case err != nil:
+		logf(cfg, "  [WARN] %s reload: %v\n", t.Name(), err)
+		res.Reload = StatusFailed
+		res.ReloadError = err.Error()
+	default:
+		if !cfg.DryRun {
+			logf(cfg, "  [OK] %s reload\n", t.Name())
+		}
+		res.Reload = StatusOK
 	}
-	return writeFile(cfg, cfg.KittyThemeConf, content)
 }
 
-func applyFuzzel(cfg *Config, s *scheme.Base16) error {
-	colorsSection, err := template.RenderString(fuzzelTemplate, s.ToMap())
+func renderKitty(cfg *Config, s *scheme.Base16) ([]File, error) {
+	content, err := renderTemplate(cfg, "kitty.mustache", s.ToMap())
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return []File{{Path: cfg.KittyThemeConf, Content: content}}, nil
+}
 
-	if cfg.DryRun {
-		logf(cfg, "  Would update [colors] in: %s\n", cfg.FuzzelIni)
-		return nil
+func renderFuzzel(cfg *Config, s *scheme.Base16) ([]File, error) {
+	colorsSection, err := renderTemplate(cfg, "fuzzel.mustache", s.ToMap())
+	if err != nil {
+		return nil, err
 	}
 
 	// Read existing file
 	existing, err := os.ReadFile(cfg.FuzzelIni)
 	if err != nil {
 		// File doesn't exist, create with just colors
-		return writeFileForce(cfg.FuzzelIni, colorsSection)
+		return []File{{Path: cfg.FuzzelIni, Content: colorsSection}}, nil
 	}
 
 	// Replace or append [colors] section
 	newContent := replaceIniSection(string(existing), "colors", colorsSection)
-	return writeFileForce(cfg.FuzzelIni, newContent)
+	return []File{{Path: cfg.FuzzelIni, Content: newContent}}, nil
 }
 
 // replaceIniSection replaces a [section] in INI content, or appends if not found
@@ -254,114 +341,95 @@ func replaceIniSection(content, sectionName, newSection string) string {
 	return strings.Join(result, "\n")
 }
 
-func writeFileForce(path, content string) error {
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("mkdir %s: %w", dir, err)
-	}
-	return os.WriteFile(path, []byte(content), 0644)
+func renderGtk4(cfg *Config, s *scheme.Base16) ([]File, error) {
+	content, err := renderTemplate(cfg, "gtk4.mustache", s.ToMap())
+	if err != nil {
+		return nil, err
+	}
+	return []File{
+		// Theme directory for plain GTK-4 apps
+		{Path: cfg.Gtk4ThemeCSS, Content: content},
+		// User CSS for libadwaita apps (they ignore theme directories)
+		{Path: cfg.Gtk4CSS, Content: content},
+	}, nil
 }
 
-func applyGtk4(cfg *Config, s *scheme.Base16) error {
-	content, err := template.RenderString(gtk4Template, s.ToMap())
+func renderGtk3(cfg *Config, s *scheme.Base16) ([]File, error) {
+	content, err := renderTemplate(cfg, "gtk3.mustache", s.ToMap())
 	if err != nil {
-		return err
+		return nil, err
 	}
-	// Write to theme directory for plain GTK-4 apps
-	if err := writeFile(cfg, cfg.Gtk4ThemeCSS, content); err != nil {
-		return err
+	files := []File{{Path: cfg.Gtk3CSS, Content: content}}
+
+	// Clean up old user CSS that would override theme colors
+	if cfg.Gtk3UserCSS != "" && exists(cfg.Gtk3UserCSS) {
+		files = append(files, File{Path: cfg.Gtk3UserCSS, Remove: true})
 	}
-	// Write to user CSS for libadwaita apps (they ignore theme directories)
-	return writeFile(cfg, cfg.Gtk4CSS, content)
+	return files, nil
 }
 
-func applyGtk3(cfg *Config, s *scheme.Base16) error {
-	content, err := template.RenderString(gtk3Template, s.ToMap())
+func renderGtk2(cfg *Config, s *scheme.Base16) ([]File, error) {
+	content, err := renderTemplate(cfg, "gtk2.mustache", s.ToMap())
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return writeFile(cfg, cfg.Gtk3CSS, content)
+	return []File{{Path: cfg.Gtk2RC, Content: content}}, nil
 }
 
-func applyGtk2(cfg *Config, s *scheme.Base16) error {
-	content, err := template.RenderString(gtk2Template, s.ToMap())
+func renderIndexTheme(cfg *Config, s *scheme.Base16) ([]File, error) {
+	content, err := renderTemplate(cfg, "index-theme.mustache", map[string]string{
+		"gtk-theme-name":     cfg.GtkThemeName,
+		"openbox-theme-name": cfg.OpenboxThemeName,
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return writeFile(cfg, cfg.Gtk2RC, content)
+	return []File{{Path: cfg.IndexTheme, Content: content}}, nil
 }
 
-func cleanupOldGtkCSS(cfg *Config) {
-	home, _ := os.UserHomeDir()
-	old := filepath.Join(home, ".config/gtk-3.0/gtk.css")
-	if _, err := os.Stat(old); err == nil {
-		if cfg.DryRun {
-			logf(cfg, "  Would remove old user CSS: %s\n", old)
-			return
-		}
-		if err := os.Remove(old); err != nil {
-			logf(cfg, "  [WARN] remove old gtk-3 css: %v\n", err)
+// renderGtkSettingsIni sets gtk-theme-name in the GTK-3/4 settings.ini files
+func renderGtkSettingsIni(cfg *Config, s *scheme.Base16) ([]File, error) {
+	var files []File
+	for _, path := range cfg.GtkSettingsInis {
+		existing, err := os.ReadFile(path)
+		if err != nil {
+			// File doesn't exist, skip (managed by NixOS/home-manager)
+			continue
 		}
-	}
-}
-
-func applyIndexTheme(cfg *Config) error {
-	return writeFile(cfg, cfg.IndexTheme, indexThemeTemplate)
-}
-
-func updateGtkSettingsIni(cfg *Config, path string) error {
-	if cfg.DryRun {
-		logf(cfg, "  Would update gtk-theme-name in: %s\n", path)
-		return nil
-	}
 
-	existing, err := os.ReadFile(path)
-	if err != nil {
-		// File doesn't exist, skip (managed by NixOS/home-manager)
-		return nil
-	}
-
-	lines := strings.Split(string(existing), "\n")
-	found := false
-	for i, line := range lines {
-		if strings.HasPrefix(line, "gtk-theme-name=") {
-			lines[i] = "gtk-theme-name=" + cfg.GtkThemeName
-			found = true
-			break
+		lines := strings.Split(string(existing), "\n")
+		found := false
+		for i, line := range lines {
+			if strings.HasPrefix(line, "gtk-theme-name=") {
+				lines[i] = "gtk-theme-name=" + cfg.GtkThemeName
+				found = true
+				break
+			}
 		}
-	}
-	if !found {
-		return nil
-	}
-
-	return os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644)
-}
-
-func applyOpenbox(cfg *Config, s *scheme.Base16) error {
-	// Ensure theme directory exists
-	themeDir := filepath.Dir(cfg.OpenboxThemerc)
-	if !cfg.DryRun {
-		if err := os.MkdirAll(themeDir, 0755); err != nil {
-			return err
+		if !found {
+			continue
 		}
+		files = append(files, File{Path: path, Content: strings.Join(lines, "\n")})
 	}
+	return files, nil
+}
 
-	content, err := template.RenderString(openboxTemplate, s.ToMap())
+func renderOpenbox(cfg *Config, s *scheme.Base16) ([]File, error) {
+	content, err := renderTemplate(cfg, "openbox.mustache", s.ToMap())
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return writeFile(cfg, cfg.OpenboxThemerc, content)
+	return []File{{Path: cfg.OpenboxThemerc, Content: content}}, nil
 }
 
-func updateLabwcRcXml(cfg *Config) error {
-	if cfg.DryRun {
-		logf(cfg, "  Would update theme name in: %s\n", cfg.LabwcRcXml)
-		return nil
-	}
+func detectLabwc(cfg *Config) bool {
+	return exists(cfg.LabwcRcXml)
+}
 
+func renderLabwcRcXml(cfg *Config, s *scheme.Base16) ([]File, error) {
 	content, err := os.ReadFile(cfg.LabwcRcXml)
 	if err != nil {
-		return fmt.Errorf("read rc.xml: %w", err)
+		return nil, fmt.Errorf("read rc.xml: %w", err)
 	}
 
 	// Replace <theme><name>...</name> with our theme name
@@ -371,13 +439,17 @@ func updateLabwcRcXml(cfg *Config) error {
 
 	if string(content) == newContent {
 		// No change needed or pattern not found
-		return nil
+		return nil, nil
 	}
 
-	return os.WriteFile(cfg.LabwcRcXml, []byte(newContent), 0644)
+	return []File{{Path: cfg.LabwcRcXml, Content: newContent}}, nil
 }
 
-func applyIconTheme(cfg *Config) error {
+func detectIconTheme(cfg *Config) bool {
+	return cfg.IconTheme != ""
+}
+
+func applyIconTheme(cfg *Config, _ []File) error {
 	if cfg.DryRun {
 		logf(cfg, "  Would set icon theme: %s\n", cfg.IconTheme)
 		return nil
@@ -391,13 +463,20 @@ func applyIconTheme(cfg *Config) error {
 	return nil
 }
 
-func applyWallpaper(cfg *Config) error {
+func detectWallpaper(cfg *Config) bool {
+	return cfg.Wallpaper != ""
+}
+
+func applyWallpaper(cfg *Config, _ []File) error {
 	if cfg.DryRun {
 		logf(cfg, "  Would set wallpaper: %s\n", cfg.Wallpaper)
 		return nil
 	}
 
-	wpPath := filepath.Join(cfg.WallpaperDir, cfg.Wallpaper)
+	wpPath := cfg.Wallpaper
+	if !filepath.IsAbs(wpPath) {
+		wpPath = filepath.Join(cfg.WallpaperDir, wpPath)
+	}
 	if err := run("swww", "img", wpPath); err != nil {
 		return fmt.Errorf("swww: %w", err)
 	}
@@ -407,7 +486,7 @@ func applyWallpaper(cfg *Config) error {
 
 func writeFile(cfg *Config, path, content string) error {
 	if cfg.DryRun {
-		logf(cfg, "  Would write to: %s\n", path)
+		previewWrite(cfg, path, content)
 		return nil
 	}
 
@@ -416,38 +495,66 @@ func writeFile(cfg *Config, path, content string) error {
 		return fmt.Errorf("mkdir %s: %w", dir, err)
 	}
 
-	return os.WriteFile(path, []byte(content), 0644)
+	if cfg.gen != nil {
+		if err := cfg.gen.snapshot(path); err != nil {
+			return err
+		}
+	}
+	return atomicWrite(path, []byte(content), 0)
 }
 
-func triggerReloads(cfg *Config) {
+func removeFile(cfg *Config, path string) error {
 	if cfg.DryRun {
-		logln(cfg, "  Would run: pkill -SIGUSR1 kitty")
-		logln(cfg, "  Would run: labwc -r")
-		logln(cfg, "  Would run: dconf toggle gtk-theme")
-		return
+		previewRemove(cfg, path)
+		return nil
+	}
+	if cfg.gen != nil {
+		if err := cfg.gen.snapshot(path); err != nil {
+			return err
+		}
 	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove %s: %w", path, err)
+	}
+	return nil
+}
 
-	// Kitty - SIGUSR1 tells kitty to reload its config
-	if err := run("pkill", "-SIGUSR1", "kitty"); err != nil {
-		logf(cfg, "  [WARN] kitty reload: %v\n", err)
-	} else {
-		logln(cfg, "  [OK] kitty reload")
+// reloadKitty sends SIGUSR1, which tells kitty to reload its config
+func reloadKitty(cfg *Config) error {
+	if cfg.DryRun {
+		logln(cfg, "  Would run: pkill -SIGUSR1 kitty")
+		return nil
 	}
+	err := run("pkill", "-SIGUSR1", "kitty")
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		// pkill exits 1 when no kitty is running, so there is nothing to reload
+		return nil
+	}
+	return err
+}
 
-	// LabWC
-	if err := run("labwc", "-r"); err != nil {
-		logf(cfg, "  [WARN] labwc reconfigure: %v\n", err)
-	} else {
-		logln(cfg, "  [OK] labwc reconfigure")
+func reloadLabwc(cfg *Config) error {
+	if cfg.DryRun {
+		logln(cfg, "  Would run: labwc -r")
+		return nil
 	}
+	return run("labwc", "-r")
+}
 
-	// GTK reload via dconf toggle
-	_ = run("dconf", "write", "/org/gnome/desktop/interface/gtk-theme", "'dummy'")
-	if err := run("dconf", "write", "/org/gnome/desktop/interface/gtk-theme", fmt.Sprintf("'%s'", cfg.GtkThemeName)); err != nil {
-		logf(cfg, "  [WARN] gtk reload: %v\n", err)
-	} else {
-		logln(cfg, "  [OK] gtk reload")
+// reloadGtk toggles the dconf gtk-theme so running GTK apps re-read it.
+// It runs once per apply, however many GTK targets were written.
+func reloadGtk(cfg *Config) error {
+	if cfg.gtkReloaded {
+		return nil
+	}
+	cfg.gtkReloaded = true
+	if cfg.DryRun {
+		logln(cfg, "  Would run: dconf toggle gtk-theme")
+		return nil
 	}
+	_ = run("dconf", "write", "/org/gnome/desktop/interface/gtk-theme", "'dummy'")
+	return run("dconf", "write", "/org/gnome/desktop/interface/gtk-theme", fmt.Sprintf("'%s'", cfg.GtkThemeName))
 }
 
 func touchFerritebarConfig(cfg *Config) error {
@@ -491,9 +598,11 @@ func logln(cfg *Config, args ...any) {
 	fmt.Println(args...)
 }
 
+// run runs a command for its side effects. Its output goes to stderr so
+// that stdout only carries our own output, such as the --json report.
 func run(name string, args ...string) error {
 	cmd := exec.Command(name, args...)
-	cmd.Stdout = os.Stdout
+	cmd.Stdout = os.Stderr
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
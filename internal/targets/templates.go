@@ -3,8 +3,10 @@ package targets
 // Embedded templates for each target
 // These are based on tinted-theming and Stylix templates
 
-const kittyTemplate = `# Base16 {{scheme-name}}
-# Scheme author: {{scheme-author}}
+// Kitty bright colors (color9–14) use the base24 bright slots; base16
+// schemes fall back to the normal colors
+const kittyTemplate = `# Base16 {{{scheme-name}}}
+# Scheme author: {{{scheme-author}}}
 # Template: base16changer
 
 background #{{base00-hex}}
@@ -40,12 +42,12 @@ color7 #{{base05-hex}}
 
 # bright
 color8 #{{base03-hex}}
-color9 #{{base08-hex}}
-color10 #{{base0B-hex}}
-color11 #{{base0A-hex}}
-color12 #{{base0D-hex}}
-color13 #{{base0E-hex}}
-color14 #{{base0C-hex}}
+color9 #{{base12-hex}}
+color10 #{{base14-hex}}
+color11 #{{base13-hex}}
+color12 #{{base16-hex}}
+color13 #{{base17-hex}}
+color14 #{{base15-hex}}
 color15 #{{base07-hex}}
 
 # extended base16
@@ -57,8 +59,8 @@ color20 #{{base04-hex}}
 color21 #{{base06-hex}}
 `
 
-const fuzzelTemplate = `# Base16 {{scheme-name}}
-# Scheme author: {{scheme-author}}
+const fuzzelTemplate = `# Base16 {{{scheme-name}}}
+# Scheme author: {{{scheme-author}}}
 
 [colors]
 background={{base01-hex}}f2
@@ -74,8 +76,8 @@ border={{base0D-hex}}ff
 const waybarTemplate = ``
 
 // GTK-4 template based on Stylix's comprehensive libadwaita support
-const gtk4Template = `/* Base16 {{scheme-name}} */
-/* Scheme author: {{scheme-author}} */
+const gtk4Template = `/* Base16 {{{scheme-name}}} */
+/* Scheme author: {{{scheme-author}}} */
 
 @define-color accent_color #{{base0D-hex}};
 @define-color accent_bg_color #{{base0D-hex}};
@@ -105,7 +107,7 @@ const gtk4Template = `/* Base16 {{scheme-name}} */
 
 @define-color headerbar_bg_color #{{base01-hex}};
 @define-color headerbar_fg_color #{{base05-hex}};
-@define-color headerbar_border_color rgba({{base01-dec-r}}, {{base01-dec-g}}, {{base01-dec-b}}, 0.7);
+@define-color headerbar_border_color rgba({{base01-rgb-r}}, {{base01-rgb-g}}, {{base01-rgb-b}}, 0.7);
 @define-color headerbar_backdrop_color @window_bg_color;
 @define-color headerbar_shade_color rgba(0, 0, 0, 0.07);
 @define-color headerbar_darker_shade_color rgba(0, 0, 0, 0.07);
@@ -182,8 +184,8 @@ const gtk4Template = `/* Base16 {{scheme-name}} */
 `
 
 // GTK-3 template — base16 colors + FlatColor widget styling
-const gtk3Template = `/* Base16 {{scheme-name}} */
-/* Scheme author: {{scheme-author}} */
+const gtk3Template = `/* Base16 {{{scheme-name}}} */
+/* Scheme author: {{{scheme-author}}} */
 
 /* Base16 color scheme */
 @define-color bg_color #{{base00-hex}};
@@ -270,8 +272,8 @@ const gtk3Template = `/* Base16 {{scheme-name}} */
 `
 
 // GTK-2 template — base16 color scheme + FlatColor widget styling
-const gtk2Template = `# Base16 {{scheme-name}}
-# Scheme author: {{scheme-author}}
+const gtk2Template = `# Base16 {{{scheme-name}}}
+# Scheme author: {{{scheme-author}}}
 
 gtk-color-scheme = "bg_color:#{{base00-hex}}
 color0:#{{base00-hex}}
@@ -292,19 +294,19 @@ include "../../FlatColor/gtk-2.0/gtkrc"
 // index.theme for ~/.themes/Base16/
 const indexThemeTemplate = `[Desktop Entry]
 Type=X-GNOME-Metatheme
-Name=Base16
+Name={{gtk-theme-name}}
 Comment=Base16 color scheme
 Encoding=UTF-8
 
 [X-GNOME-Metatheme]
-GtkTheme=Base16
-MetacityTheme=Base16
+GtkTheme={{gtk-theme-name}}
+MetacityTheme={{openbox-theme-name}}
 IconTheme=Adwaita
 `
 
 // Openbox themerc for labwc
-const openboxTemplate = `# Base16 {{scheme-name}}
-# Scheme author: {{scheme-author}}
+const openboxTemplate = `# Base16 {{{scheme-name}}}
+# Scheme author: {{{scheme-author}}}
 
 # Window geometry
 border.width: 1
@@ -385,3 +387,18 @@ osd.bg.color: #{{base00-hex}}
 osd.border.color: #{{base02-hex}}
 osd.label.text.color: #{{base05-hex}}
 `
+
+// builtinTemplates names the embedded templates as they are looked up in
+// TemplatesDir and written by `templates dump`
+var builtinTemplates = []struct {
+	name    string
+	content string
+}{
+	{"kitty.mustache", kittyTemplate},
+	{"fuzzel.mustache", fuzzelTemplate},
+	{"gtk4.mustache", gtk4Template},
+	{"gtk3.mustache", gtk3Template},
+	{"gtk2.mustache", gtk2Template},
+	{"index-theme.mustache", indexThemeTemplate},
+	{"openbox.mustache", openboxTemplate},
+}
@@ -0,0 +1,47 @@
+package targets
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/jaycee1285/base16changer/internal/convert"
+	"github.com/jaycee1285/base16changer/internal/scheme"
+)
+
+// tokensBase names the design-token files, so tools can import a fixed
+// path (base16.css, base16.nix …) whichever scheme is active
+const tokensBase = "base16"
+
+func detectTokens(cfg *Config) bool {
+	return len(cfg.TokenFormats) > 0
+}
+
+// renderTokens writes the palette in each configured design-token format
+// into TokensDir
+func renderTokens(cfg *Config, s *scheme.Base16) ([]File, error) {
+	var files []File
+	for _, f := range cfg.TokenFormats {
+		data, err := convert.Export(s, f)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{
+			Path:    filepath.Join(cfg.TokensDir, tokensBase+convert.Extension(f)),
+			Content: string(data),
+		})
+	}
+	return files, nil
+}
+
+// ParseTokenFormats checks a list of design-token format names
+func ParseTokenFormats(names []string) ([]convert.Format, error) {
+	var out []convert.Format
+	for _, n := range names {
+		f, err := convert.ParseTokenFormat(strings.TrimSpace(n))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, f)
+	}
+	return out, nil
+}
@@ -0,0 +1,266 @@
+package template
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/lucasb-eyer/go-colorful"
+
+	"github.com/jaycee1285/base16changer/internal/scheme"
+)
+
+// Variables can be piped through color filters, which is not part of the
+// Mustache spec:
+//
+//	{{base00-hex | alpha 85%}}            1d2021d9
+//	{{base0D-hex | lighten 10%}}          lightness +10 (OKLCH)
+//	{{base08-hex | mix base00-hex 25%}}   25% of the way to base00 (OKLab)
+//	{{base0D-hex | to-rgb}}               rgb(131, 165, 152)
+//
+// Color filters change the color; format filters turn it into text and
+// come last. Without a format filter the result is hex like the -hex
+// variables, with two alpha digits when alpha was set.
+
+// filter is one "name arg…" step of a pipe
+type filter struct {
+	name string
+	args []string
+}
+
+// filterSpec describes a filter's arguments
+type filterSpec struct {
+	name             string
+	minArgs, maxArgs int
+	format           bool // turns the color into text, so it must come last
+	usage            string
+}
+
+var filterSpecs = []filterSpec{
+	{"alpha", 1, 1, false, "alpha 0.85 or alpha 85%"},
+	{"lighten", 1, 1, false, "lighten 10%"},
+	{"darken", 1, 1, false, "darken 10%"},
+	{"saturate", 1, 1, false, "saturate 20%"},
+	{"desaturate", 1, 1, false, "desaturate 20%"},
+	{"mix", 1, 2, false, "mix base00-hex 25% or mix #ffffff"},
+	{"hex", 0, 0, true, "hex"},
+	{"uppercase-hex", 0, 0, true, "uppercase-hex"},
+	{"hash", 0, 0, true, "hash"},
+	{"to-rgb", 0, 0, true, "to-rgb"},
+	{"to-hsl", 0, 0, true, "to-hsl"},
+	{"to-float", 0, 0, true, "to-float"},
+}
+
+func findFilter(name string) (filterSpec, bool) {
+	for _, f := range filterSpecs {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return filterSpec{}, false
+}
+
+// parseFilters splits "name | filter arg | …" into the variable name and
+// its filters
+func parseFilters(tag string) (string, []filter, error) {
+	parts := strings.Split(tag, "|")
+	name := strings.TrimSpace(parts[0])
+	var filters []filter
+	for i, p := range parts[1:] {
+		f := strings.Fields(p)
+		if len(f) == 0 {
+			return "", nil, fmt.Errorf("empty filter in %q", tag)
+		}
+		spec, ok := findFilter(f[0])
+		if !ok {
+			return "", nil, fmt.Errorf("unknown filter %q (expected one of: %s)", f[0], filterNames())
+		}
+		if n := len(f) - 1; n < spec.minArgs || n > spec.maxArgs {
+			return "", nil, fmt.Errorf("filter %s: expected %s", f[0], spec.usage)
+		}
+		if spec.format && i < len(parts)-2 {
+			return "", nil, fmt.Errorf("filter %s must come last", f[0])
+		}
+		filters = append(filters, filter{f[0], f[1:]})
+	}
+	return name, filters, nil
+}
+
+func filterNames() string {
+	names := make([]string, len(filterSpecs))
+	for i, f := range filterSpecs {
+		names[i] = f.name
+	}
+	return strings.Join(names, ", ")
+}
+
+// rgba is a color with alpha; hasAlpha records whether alpha was set, so
+// plain colors keep six hex digits
+type rgba struct {
+	c        colorful.Color
+	alpha    float64
+	hasAlpha bool
+}
+
+// parseRGBA reads "rrggbb", "#rrggbb" or the same with two alpha digits
+func parseRGBA(s string) (rgba, bool) {
+	h := strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(h) != 6 && len(h) != 8 {
+		return rgba{}, false
+	}
+	c, err := colorful.Hex("#" + h[:6])
+	if err != nil {
+		return rgba{}, false
+	}
+	v := rgba{c: c, alpha: 1}
+	if len(h) == 8 {
+		a, err := strconv.ParseUint(h[6:], 16, 8)
+		if err != nil {
+			return rgba{}, false
+		}
+		v.alpha, v.hasAlpha = float64(a)/255, true
+	}
+	return v, true
+}
+
+// applyFilters runs the filters over a variable's value. resolve looks up
+// color arguments given by variable name.
+func applyFilters(value string, filters []filter, resolve func(name string) (string, bool)) (string, error) {
+	v, ok := parseRGBA(value)
+	if !ok {
+		return "", fmt.Errorf("%q is not a hex color", value)
+	}
+	for _, f := range filters {
+		var err error
+		switch f.name {
+		case "alpha":
+			var a float64
+			if a, err = fraction(f.args[0]); err == nil {
+				v.alpha, v.hasAlpha = a, true
+			}
+		case "lighten", "darken", "saturate", "desaturate":
+			var p float64
+			if p, err = percent(f.args[0]); err == nil {
+				v.c = adjustOKLCH(v.c, f.name, p)
+			}
+		case "mix":
+			v, err = mix(v, f.args, resolve)
+		case "hex":
+			return v.hex(), nil
+		case "uppercase-hex":
+			return strings.ToUpper(v.hex()), nil
+		case "hash":
+			return "#" + v.hex(), nil
+		case "to-rgb":
+			r, g, b := v.c.Clamped().RGB255()
+			if v.hasAlpha {
+				return fmt.Sprintf("rgba(%d, %d, %d, %s)", r, g, b, formatAlpha(v.alpha)), nil
+			}
+			return fmt.Sprintf("rgb(%d, %d, %d)", r, g, b), nil
+		case "to-hsl":
+			h, s, l := v.c.Clamped().Hsl()
+			hsl := fmt.Sprintf("%d, %d%%, %d%%", int(math.Round(h))%360, int(math.Round(s*100)), int(math.Round(l*100)))
+			if v.hasAlpha {
+				return fmt.Sprintf("hsla(%s, %s)", hsl, formatAlpha(v.alpha)), nil
+			}
+			return "hsl(" + hsl + ")", nil
+		case "to-float":
+			c := v.c.Clamped()
+			out := fmt.Sprintf("%.8f, %.8f, %.8f", c.R, c.G, c.B)
+			if v.hasAlpha {
+				out += fmt.Sprintf(", %.8f", v.alpha)
+			}
+			return out, nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("filter %s: %w", f.name, err)
+		}
+	}
+	return v.hex(), nil
+}
+
+// hex formats the color like the -hex variables, adding alpha digits when
+// alpha was set
+func (v rgba) hex() string {
+	h := strings.TrimPrefix(v.c.Clamped().Hex(), "#")
+	if v.hasAlpha {
+		h += fmt.Sprintf("%02x", int(math.Round(v.alpha*255)))
+	}
+	return h
+}
+
+// adjustOKLCH lightens, darkens, saturates or desaturates by p percent,
+// the way --adjust changes brightness and saturation
+func adjustOKLCH(c colorful.Color, op string, p float64) colorful.Color {
+	l, chroma, hue := c.OkLch()
+	switch op {
+	case "lighten":
+		l += p / 100
+	case "darken":
+		l -= p / 100
+	case "saturate":
+		chroma *= 1 + p/100
+	case "desaturate":
+		chroma *= math.Max(0, 1-p/100)
+	}
+	out, _ := colorful.Hex("#" + scheme.FromOKLCH(math.Max(0, math.Min(1, l)), math.Max(0, chroma), hue))
+	return out
+}
+
+// mix blends toward another color, given as a variable name or a hex
+// literal, by a weight (default 50%). Alpha is blended too.
+func mix(v rgba, args []string, resolve func(name string) (string, bool)) (rgba, error) {
+	other, ok := parseRGBA(args[0])
+	if !ok {
+		value, found := resolve(args[0])
+		if !found {
+			return v, fmt.Errorf("undefined color %q", args[0])
+		}
+		if other, ok = parseRGBA(value); !ok {
+			return v, fmt.Errorf("%s = %q is not a hex color", args[0], value)
+		}
+	}
+	w := 0.5
+	if len(args) > 1 {
+		var err error
+		if w, err = fraction(args[1]); err != nil {
+			return v, err
+		}
+	}
+	v.c = v.c.BlendOkLab(other.c, w).Clamped()
+	if v.hasAlpha || other.hasAlpha {
+		v.alpha = v.alpha + (other.alpha-v.alpha)*w
+		v.hasAlpha = true
+	}
+	return v, nil
+}
+
+// percent reads "10%" or "10" as 10
+func percent(s string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", s)
+	}
+	return v, nil
+}
+
+// fraction reads "85%" or "0.85" as 0.85
+func fraction(s string) (float64, error) {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", s)
+	}
+	if strings.HasSuffix(s, "%") {
+		v /= 100
+	}
+	if v < 0 || v > 1 {
+		return 0, fmt.Errorf("%q is out of range (0–1 or 0–100%%)", s)
+	}
+	return v, nil
+}
+
+// formatAlpha prints alpha with at most three decimals
+func formatAlpha(a float64) string {
+	return strconv.FormatFloat(math.Round(a*1000)/1000, 'f', -1, 64)
+}
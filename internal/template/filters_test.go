@@ -0,0 +1,67 @@
+package template
+
+import (
+	"strings"
+	"testing"
+)
+
+var filterData = map[string]string{
+	"base00-hex": "1d2021",
+	"base05-hex": "ffffff",
+	"base0D-hex": "83a598",
+}
+
+func TestFilters(t *testing.T) {
+	cases := []struct {
+		name, template, want string
+	}{
+		{"alpha percent", "{{base00-hex | alpha 85%}}", "1d2021d9"},
+		{"alpha fraction", "{{base00-hex | alpha 0.85}}", "1d2021d9"},
+		{"mix variable", "{{base00-hex | mix base05-hex}}", "858787"},
+		{"mix variable none", "{{base00-hex | mix base05-hex 0%}}", "1d2021"},
+		{"mix hex", "{{base00-hex | mix #ffffff 100%}}", "ffffff"},
+		{"mix hex without hash", "{{base00-hex | mix ffffff 25%}}", "4e5051"},
+		{"mix keeps alpha", "{{base00-hex | alpha 85% | mix base05-hex 100%}}", "ffffffff"},
+		{"to-rgb", "{{base0D-hex | to-rgb}}", "rgb(131, 165, 152)"},
+		{"to-rgb alpha", "{{base0D-hex | alpha 50% | to-rgb}}", "rgba(131, 165, 152, 0.5)"},
+		{"to-hsl", "{{base0D-hex | to-hsl}}", "hsl(157, 16%, 58%)"},
+		{"to-hsl alpha", "{{base0D-hex | alpha 0.25 | to-hsl}}", "hsla(157, 16%, 58%, 0.25)"},
+		{"hash", "{{base0D-hex | hash}}", "#83a598"},
+		{"uppercase-hex", "{{base0D-hex | uppercase-hex}}", "83A598"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := RenderString(c.template, filterData)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.want {
+				t.Errorf("%s = %q, want %q", c.template, got, c.want)
+			}
+		})
+	}
+}
+
+func TestFilterErrors(t *testing.T) {
+	cases := []struct {
+		name, template, want string
+	}{
+		{"fraction out of range", "{{base00-hex | alpha 1.5}}", `filter alpha: "1.5" is out of range`},
+		{"percent out of range", "{{base00-hex | alpha 120%}}", `filter alpha: "120%" is out of range`},
+		{"format not last", "{{base00-hex | to-rgb | alpha 50%}}", "filter to-rgb must come last"},
+		{"unknown filter", "{{base00-hex | blur}}", `unknown filter "blur" (expected one of: alpha,`},
+		{"missing argument", "{{base00-hex | alpha}}", "filter alpha: expected alpha 0.85 or alpha 85%"},
+		{"undefined mix color", "{{base00-hex | mix nope}}", `filter mix: undefined color "nope"`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := RenderString(c.template, filterData)
+			if err == nil {
+				t.Fatalf("expected an error containing %q", c.want)
+			}
+			if !strings.Contains(err.Error(), c.want) {
+				t.Errorf("error %q does not contain %q", err, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,473 @@
+package template
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// This file implements Mustache as described by the spec at
+// https://github.com/mustache/spec: variables, sections, inverted sections,
+// comments, partials, set delimiters and standalone-line handling. Lambdas
+// and inheritance, the optional modules, are not supported.
+
+type kind int
+
+const (
+	kindText     kind = iota
+	kindVar           // {{name}}, HTML-escaped
+	kindRaw           // {{{name}}} or {{&name}}
+	kindSection       // {{#name}} … {{/name}}
+	kindInverted      // {{^name}} … {{/name}}
+	kindClose         // {{/name}}
+	kindComment       // {{! … }}
+	kindPartial       // {{>name}}
+	kindDelims        // {{=<% %>=}}
+)
+
+// token is one piece of a template as scanned, before nesting
+type token struct {
+	kind   kind
+	text   string // text, or the tag name
+	line   int
+	indent string // whitespace before a standalone partial
+
+	filters []filter // color filters piped after a variable
+}
+
+// node is a parsed template element; sections hold their body
+type node struct {
+	token
+	children []node
+}
+
+// standalone kinds may sit alone on a line, which is then removed
+func (k kind) standalone() bool {
+	switch k {
+	case kindSection, kindInverted, kindClose, kindComment, kindPartial, kindDelims:
+		return true
+	}
+	return false
+}
+
+// parse scans a template and nests its sections
+func parse(src string) ([]node, error) {
+	tokens, err := scan(src)
+	if err != nil {
+		return nil, err
+	}
+	tokens = stripStandalone(tokens)
+
+	type open struct {
+		tok   token
+		nodes []node
+	}
+	var stack []open
+	var nodes []node
+	for _, t := range tokens {
+		switch t.kind {
+		case kindComment, kindDelims:
+			continue
+		case kindSection, kindInverted:
+			stack = append(stack, open{t, nodes})
+			nodes = nil
+		case kindClose:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("line %d: {{/%s}} without an open section", t.line, t.text)
+			}
+			top := stack[len(stack)-1]
+			if top.tok.text != t.text {
+				return nil, fmt.Errorf("line %d: {{/%s}} closes section %q opened on line %d", t.line, t.text, top.tok.text, top.tok.line)
+			}
+			stack = stack[:len(stack)-1]
+			section := node{token: top.tok, children: nodes}
+			nodes = append(top.nodes, section)
+		default:
+			nodes = append(nodes, node{token: t})
+		}
+	}
+	if len(stack) > 0 {
+		top := stack[len(stack)-1]
+		return nil, fmt.Errorf("line %d: section %q is never closed", top.tok.line, top.tok.text)
+	}
+	return nodes, nil
+}
+
+// scan splits a template into text and tags. Text is split after every
+// newline so stripStandalone can work line by line.
+func scan(src string) ([]token, error) {
+	var tokens []token
+	otag, ctag := "{{", "}}"
+	line := 1
+
+	text := func(s string) {
+		for s != "" {
+			n := strings.IndexByte(s, '\n') + 1
+			if n == 0 {
+				n = len(s)
+			}
+			tokens = append(tokens, token{kind: kindText, text: s[:n], line: line})
+			if s[n-1] == '\n' {
+				line++
+			}
+			s = s[n:]
+		}
+	}
+
+	for src != "" {
+		i := strings.Index(src, otag)
+		if i < 0 {
+			text(src)
+			break
+		}
+		text(src[:i])
+		src = src[i+len(otag):]
+
+		t := token{kind: kindVar, line: line}
+		closing := ctag
+		if src != "" {
+			switch src[0] {
+			case '{':
+				t.kind, closing = kindRaw, "}"+ctag
+			case '&':
+				t.kind = kindRaw
+			case '#':
+				t.kind = kindSection
+			case '^':
+				t.kind = kindInverted
+			case '/':
+				t.kind = kindClose
+			case '!':
+				t.kind = kindComment
+			case '>':
+				t.kind = kindPartial
+			case '=':
+				t.kind, closing = kindDelims, "="+ctag
+			}
+			if t.kind != kindVar {
+				src = src[1:]
+			}
+		}
+		end := strings.Index(src, closing)
+		if end < 0 {
+			return nil, fmt.Errorf("line %d: unclosed tag %s", t.line, otag)
+		}
+		content := src[:end]
+		line += strings.Count(content, "\n")
+		src = src[end+len(closing):]
+		t.text = strings.TrimSpace(content)
+
+		switch t.kind {
+		case kindComment:
+		case kindDelims:
+			f := strings.Fields(t.text)
+			if len(f) != 2 || strings.Contains(t.text, "=") {
+				return nil, fmt.Errorf("line %d: invalid delimiters %q", t.line, t.text)
+			}
+			otag, ctag = f[0], f[1]
+		case kindVar, kindRaw:
+			if strings.Contains(t.text, "|") {
+				var err error
+				if t.text, t.filters, err = parseFilters(t.text); err != nil {
+					return nil, fmt.Errorf("line %d: %w", t.line, err)
+				}
+			}
+			fallthrough
+		default:
+			if t.text == "" {
+				return nil, fmt.Errorf("line %d: empty tag", t.line)
+			}
+		}
+		tokens = append(tokens, t)
+	}
+	return tokens, nil
+}
+
+// stripStandalone removes the whitespace and newline around a tag that is
+// alone on its line (sections, comments, partials, delimiters), and
+// records the indentation of standalone partials
+func stripStandalone(tokens []token) []token {
+	var out []token
+	for start := 0; start < len(tokens); {
+		end := start
+		for end < len(tokens) {
+			t := tokens[end]
+			end++
+			if t.kind == kindText && strings.HasSuffix(t.text, "\n") {
+				break
+			}
+		}
+		lineToks := tokens[start:end]
+		start = end
+
+		tags, blank := 0, true
+		for _, t := range lineToks {
+			switch {
+			case t.kind == kindText:
+				if strings.Trim(t.text, " \t\r\n") != "" {
+					blank = false
+				}
+			case t.kind.standalone():
+				tags++
+			default:
+				blank = false
+			}
+		}
+		if tags != 1 || !blank {
+			out = append(out, lineToks...)
+			continue
+		}
+
+		indent := ""
+		for _, t := range lineToks {
+			if t.kind == kindText {
+				if !strings.HasSuffix(t.text, "\n") {
+					indent += t.text
+				}
+				continue
+			}
+			if t.kind == kindPartial {
+				t.indent = indent
+			}
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// renderer executes parsed templates against a context stack
+type renderer struct {
+	// strict makes undefined variables and missing partials errors
+	// instead of rendering as empty strings
+	strict bool
+	// partial returns the source of a partial by name
+	partial func(name string) (string, bool, error)
+	parsed  map[string][]node
+	depth   int
+}
+
+// maxPartialDepth stops recursive partials that never end
+const maxPartialDepth = 100
+
+func (r *renderer) render(b *strings.Builder, nodes []node, stack []any) error {
+	for _, n := range nodes {
+		switch n.kind {
+		case kindText:
+			b.WriteString(n.text)
+
+		case kindVar, kindRaw:
+			v, ok := lookup(stack, n.text)
+			if !ok && r.strict {
+				return fmt.Errorf("line %d: undefined variable %q", n.line, n.text)
+			}
+			s := format(v)
+			if len(n.filters) > 0 {
+				var err error
+				s, err = applyFilters(s, n.filters, func(name string) (string, bool) {
+					v, ok := lookup(stack, name)
+					return format(v), ok
+				})
+				if err != nil {
+					return fmt.Errorf("line %d: %s: %w", n.line, n.text, err)
+				}
+			}
+			if n.kind == kindVar {
+				s = escapeHTML(s)
+			}
+			b.WriteString(s)
+
+		case kindSection:
+			v, _ := lookup(stack, n.text)
+			if !truthy(v) {
+				continue
+			}
+			if items, ok := list(v); ok {
+				for _, it := range items {
+					if err := r.render(b, n.children, append(stack, it)); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			if err := r.render(b, n.children, append(stack, v)); err != nil {
+				return err
+			}
+
+		case kindInverted:
+			v, _ := lookup(stack, n.text)
+			if truthy(v) {
+				continue
+			}
+			if err := r.render(b, n.children, stack); err != nil {
+				return err
+			}
+
+		case kindPartial:
+			if err := r.renderPartial(b, n, stack); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// renderPartial renders a partial in the current context. Every line of
+// a standalone partial is indented like the tag.
+func (r *renderer) renderPartial(b *strings.Builder, n node, stack []any) error {
+	key := n.indent + "\x00" + n.text
+	nodes, ok := r.parsed[key]
+	if !ok {
+		var src string
+		var found bool
+		var err error
+		if r.partial != nil {
+			src, found, err = r.partial(n.text)
+		}
+		if err != nil {
+			return fmt.Errorf("line %d: partial %q: %w", n.line, n.text, err)
+		}
+		if !found {
+			if r.strict {
+				return fmt.Errorf("line %d: partial %q not found", n.line, n.text)
+			}
+			return nil
+		}
+		if n.indent != "" {
+			src = indentLines(src, n.indent)
+		}
+		if nodes, err = parse(src); err != nil {
+			return fmt.Errorf("partial %q: %w", n.text, err)
+		}
+		if r.parsed == nil {
+			r.parsed = map[string][]node{}
+		}
+		r.parsed[key] = nodes
+	}
+
+	if r.depth >= maxPartialDepth {
+		return fmt.Errorf("line %d: partial %q nested more than %d deep", n.line, n.text, maxPartialDepth)
+	}
+	r.depth++
+	defer func() { r.depth-- }()
+	if err := r.render(b, nodes, stack); err != nil {
+		return fmt.Errorf("partial %q: %w", n.text, err)
+	}
+	return nil
+}
+
+// indentLines prefixes every line of s, except an empty last one
+func indentLines(s, indent string) string {
+	lines := strings.SplitAfter(s, "\n")
+	for i, l := range lines {
+		if l != "" {
+			lines[i] = indent + l
+		}
+	}
+	return strings.Join(lines, "")
+}
+
+// lookup resolves a name against the context stack. "." is the current
+// context; for dotted names only the first part searches the stack, the
+// rest must be found inside it.
+func lookup(stack []any, name string) (any, bool) {
+	if name == "." {
+		if len(stack) == 0 {
+			return nil, false
+		}
+		return stack[len(stack)-1], true
+	}
+
+	parts := strings.Split(name, ".")
+	var v any
+	found := false
+	for i := len(stack) - 1; i >= 0 && !found; i-- {
+		v, found = field(stack[i], parts[0])
+	}
+	for _, p := range parts[1:] {
+		if !found {
+			break
+		}
+		v, found = field(v, p)
+	}
+	if !found {
+		return nil, false
+	}
+	return v, true
+}
+
+// field returns the value of key in a map context
+func field(ctx any, key string) (any, bool) {
+	switch m := ctx.(type) {
+	case map[string]string:
+		v, ok := m[key]
+		return v, ok
+	case map[string]any:
+		v, ok := m[key]
+		return v, ok
+	}
+	rv := reflect.ValueOf(ctx)
+	if rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String {
+		v := rv.MapIndex(reflect.ValueOf(key).Convert(rv.Type().Key()))
+		if v.IsValid() {
+			return v.Interface(), true
+		}
+	}
+	return nil, false
+}
+
+// truthy decides whether a section renders. Besides the spec's false,
+// null and empty lists, the strings "" and "false" are falsy, since
+// builder variables such as scheme-is-dark-variant are strings.
+func truthy(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != "" && v != "false"
+	}
+	if items, ok := list(v); ok {
+		return len(items) > 0
+	}
+	return true
+}
+
+// list returns the items of a slice or array value
+func list(v any) ([]any, bool) {
+	if items, ok := v.([]any); ok {
+		return items, true
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	items := make([]any, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i).Interface()
+	}
+	return items, true
+}
+
+// format converts a value to the text it interpolates as
+func format(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	}
+	return fmt.Sprint(v)
+}
+
+var htmlEscaper = strings.NewReplacer("&", "&amp;", `"`, "&quot;", "<", "&lt;", ">", "&gt;")
+
+// escapeHTML escapes the characters the spec requires for {{name}}
+func escapeHTML(s string) string {
+	return htmlEscaper.Replace(s)
+}
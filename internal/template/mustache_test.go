@@ -0,0 +1,274 @@
+package template
+
+import (
+	"strings"
+	"testing"
+)
+
+// specCase is one test from the Mustache spec (github.com/mustache/spec)
+type specCase struct {
+	name     string
+	data     any
+	template string
+	partials map[string]string
+	want     string
+}
+
+// runSpec renders spec cases without strict mode, since the spec expects
+// missing variables and partials to render as empty strings
+func runSpec(t *testing.T, cases []specCase) {
+	t.Helper()
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := &renderer{partial: func(name string) (string, bool, error) {
+				src, ok := c.partials[name]
+				return src, ok, nil
+			}}
+			nodes, err := parse(c.template)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			var b strings.Builder
+			if err := r.render(&b, nodes, []any{c.data}); err != nil {
+				t.Fatalf("render: %v", err)
+			}
+			if got := b.String(); got != c.want {
+				t.Errorf("template %q\n got %q\nwant %q", c.template, got, c.want)
+			}
+		})
+	}
+}
+
+type m = map[string]any
+
+func TestSpecInterpolation(t *testing.T) {
+	runSpec(t, []specCase{
+		{"No Interpolation", m{}, "Hello from {Mustache}!\n", nil, "Hello from {Mustache}!\n"},
+		{"Basic Interpolation", m{"subject": "world"}, "Hello, {{subject}}!\n", nil, "Hello, world!\n"},
+		{"HTML Escaping", m{"forbidden": `& " < >`}, "These characters should be HTML escaped: {{forbidden}}\n", nil,
+			"These characters should be HTML escaped: &amp; &quot; &lt; &gt;\n"},
+		{"Triple Mustache", m{"forbidden": `& " < >`}, "These characters should not be HTML escaped: {{{forbidden}}}\n", nil,
+			"These characters should not be HTML escaped: & \" < >\n"},
+		{"Ampersand", m{"forbidden": `& " < >`}, "These characters should not be HTML escaped: {{&forbidden}}\n", nil,
+			"These characters should not be HTML escaped: & \" < >\n"},
+		{"Basic Integer Interpolation", m{"mph": 85}, `"{{mph}} miles an hour!"`, nil, `"85 miles an hour!"`},
+		{"Triple Mustache Integer Interpolation", m{"mph": 85}, `"{{{mph}}} miles an hour!"`, nil, `"85 miles an hour!"`},
+		{"Ampersand Integer Interpolation", m{"mph": 85}, `"{{&mph}} miles an hour!"`, nil, `"85 miles an hour!"`},
+		{"Basic Decimal Interpolation", m{"power": 1.210}, `"{{power}} jiggawatts!"`, nil, `"1.21 jiggawatts!"`},
+		{"Basic Null Interpolation", m{"cannot": nil}, "I ({{cannot}}) be seen!", nil, "I () be seen!"},
+		{"Basic Context Miss Interpolation", m{}, "I ({{cannot}}) be seen!", nil, "I () be seen!"},
+		{"Dotted Names - Basic Interpolation", m{"person": m{"name": "Joe"}},
+			`"{{person.name}}" == "{{#person}}{{name}}{{/person}}"`, nil, `"Joe" == "Joe"`},
+		{"Dotted Names - Triple Mustache Interpolation", m{"person": m{"name": "Joe"}},
+			`"{{{person.name}}}" == "{{#person}}{{{name}}}{{/person}}"`, nil, `"Joe" == "Joe"`},
+		{"Dotted Names - Arbitrary Depth", m{"a": m{"b": m{"c": m{"d": m{"e": m{"name": "Phil"}}}}}},
+			`"{{a.b.c.d.e.name}}" == "Phil"`, nil, `"Phil" == "Phil"`},
+		{"Dotted Names - Broken Chains", m{"a": m{}}, `"{{a.b.c}}" == ""`, nil, `"" == ""`},
+		{"Dotted Names - Broken Chain Resolution", m{"a": m{"b": m{}}, "c": m{"name": "Jim"}},
+			`"{{a.b.c.name}}" == ""`, nil, `"" == ""`},
+		{"Dotted Names - Initial Resolution",
+			m{"a": m{"b": m{"c": m{"d": m{"e": m{"name": "Phil"}}}}}, "b": m{"c": m{"d": m{"e": m{"name": "Wrong"}}}}},
+			`"{{#a}}{{b.c.d.e.name}}{{/a}}" == "Phil"`, nil, `"Phil" == "Phil"`},
+		{"Dotted Names - Context Precedence", m{"a": m{"b": m{}}, "b": m{"c": "ERROR"}},
+			`{{#a}}{{b.c}}{{/a}}`, nil, ""},
+		{"Implicit Iterators - Basic Interpolation", "world", "Hello, {{.}}!\n", nil, "Hello, world!\n"},
+		{"Implicit Iterators - HTML Escaping", `& " < >`, "These characters should be HTML escaped: {{.}}\n", nil,
+			"These characters should be HTML escaped: &amp; &quot; &lt; &gt;\n"},
+		{"Implicit Iterators - Triple Mustache", `& " < >`, "These characters should not be HTML escaped: {{{.}}}\n", nil,
+			"These characters should not be HTML escaped: & \" < >\n"},
+		{"Interpolation - Surrounding Whitespace", m{"string": "---"}, "| {{string}} |", nil, "| --- |"},
+		{"Triple Mustache - Surrounding Whitespace", m{"string": "---"}, "| {{{string}}} |", nil, "| --- |"},
+		{"Interpolation - Standalone", m{"string": "---"}, "  {{string}}\n", nil, "  ---\n"},
+		{"Ampersand - Standalone", m{"string": "---"}, "  {{&string}}\n", nil, "  ---\n"},
+		{"Interpolation With Padding", m{"string": "---"}, "|{{ string }}|", nil, "|---|"},
+		{"Triple Mustache With Padding", m{"string": "---"}, "|{{{ string }}}|", nil, "|---|"},
+		{"Ampersand With Padding", m{"string": "---"}, "|{{& string }}|", nil, "|---|"},
+	})
+}
+
+func TestSpecSections(t *testing.T) {
+	runSpec(t, []specCase{
+		{"Truthy", m{"boolean": true}, `"{{#boolean}}This should be rendered.{{/boolean}}"`, nil, `"This should be rendered."`},
+		{"Falsey", m{"boolean": false}, `"{{#boolean}}This should not be rendered.{{/boolean}}"`, nil, `""`},
+		{"Null is falsey", m{"null": nil}, `"{{#null}}This should not be rendered.{{/null}}"`, nil, `""`},
+		{"Context", m{"context": m{"name": "Joe"}}, `"{{#context}}Hi {{name}}.{{/context}}"`, nil, `"Hi Joe."`},
+		{"Parent contexts", m{"a": "foo", "b": "wrong", "sec": m{"b": "bar"}, "c": m{"d": "baz"}},
+			`"{{#sec}}{{a}}, {{b}}, {{c.d}}{{/sec}}"`, nil, `"foo, bar, baz"`},
+		{"Variable test", m{"foo": "bar"}, `"{{#foo}}{{.}} is {{foo}}{{/foo}}"`, nil, `"bar is bar"`},
+		{"List Contexts", m{"tops": []any{m{"tname": m{"upper": "A", "lower": "a"},
+			"middles": []any{m{"mname": "1", "bottoms": []any{m{"bname": "x"}, m{"bname": "y"}}}}}}},
+			"{{#tops}}{{#middles}}{{tname.lower}}{{mname}}.{{#bottoms}}{{tname.upper}}{{mname}}{{bname}}.{{/bottoms}}{{/middles}}{{/tops}}",
+			nil, "a1.A1x.A1y."},
+		{"Deeply Nested Contexts", m{"a": m{"one": 1}, "b": m{"two": 2}, "c": m{"three": 3, "d": m{"four": 4, "five": 5}}},
+			"{{#a}}\n{{one}}\n{{#b}}\n{{one}}{{two}}{{one}}\n{{#c}}\n{{one}}{{two}}{{three}}{{two}}{{one}}\n{{#d}}\n{{one}}{{two}}{{three}}{{four}}{{three}}{{two}}{{one}}\n{{#five}}\n{{one}}{{two}}{{three}}{{four}}{{five}}{{four}}{{three}}{{two}}{{one}}\n{{/five}}\n{{/d}}\n{{/c}}\n{{/b}}\n{{/a}}\n",
+			nil, "1\n121\n12321\n1234321\n123454321\n"},
+		{"List", m{"list": []any{m{"item": 1}, m{"item": 2}, m{"item": 3}}}, `"{{#list}}{{item}}{{/list}}"`, nil, `"123"`},
+		{"Empty List", m{"list": []any{}}, `"{{#list}}Yay lists!{{/list}}"`, nil, `""`},
+		{"Doubled", m{"bool": true, "two": "second"},
+			"{{#bool}}\n* first\n{{/bool}}\n* {{two}}\n{{#bool}}\n* third\n{{/bool}}\n", nil, "* first\n* second\n* third\n"},
+		{"Nested (Truthy)", m{"bool": true}, "| A {{#bool}}B {{#bool}}C{{/bool}} D{{/bool}} E |", nil, "| A B C D E |"},
+		{"Nested (Falsey)", m{"bool": false}, "| A {{#bool}}B {{#bool}}C{{/bool}} D{{/bool}} E |", nil, "| A  E |"},
+		{"Context Misses", m{}, "[{{#missing}}Found key 'missing'!{{/missing}}]", nil, "[]"},
+		{"Implicit Iterator - String", m{"list": []any{"a", "b", "c", "d", "e"}}, `"{{#list}}({{.}}){{/list}}"`, nil, `"(a)(b)(c)(d)(e)"`},
+		{"Implicit Iterator - Integer", m{"list": []any{1, 2, 3, 4, 5}}, `"{{#list}}({{.}}){{/list}}"`, nil, `"(1)(2)(3)(4)(5)"`},
+		{"Implicit Iterator - Decimal", m{"list": []any{1.10, 2.20, 3.30, 4.40, 5.50}}, `"{{#list}}({{.}}){{/list}}"`, nil,
+			`"(1.1)(2.2)(3.3)(4.4)(5.5)"`},
+		{"Implicit Iterator - Array", m{"list": []any{[]any{1, 2, 3}, []any{"a", "b", "c"}}},
+			`"{{#list}}({{#.}}{{.}}{{/.}}){{/list}}"`, nil, `"(123)(abc)"`},
+		{"Implicit Iterator - HTML Escaping", m{"list": []any{"&", `"`, "<", ">"}}, `"{{#list}}({{.}}){{/list}}"`, nil,
+			`"(&amp;)(&quot;)(&lt;)(&gt;)"`},
+		{"Implicit Iterator - Triple mustache", m{"list": []any{"&", `"`, "<", ">"}}, `"{{#list}}({{{.}}}){{/list}}"`, nil,
+			`"(&)(")(<)(>)"`},
+		{"Dotted Names - Truthy", m{"a": m{"b": m{"c": true}}}, `"{{#a.b.c}}Here{{/a.b.c}}" == "Here"`, nil, `"Here" == "Here"`},
+		{"Dotted Names - Falsey", m{"a": m{"b": m{"c": false}}}, `"{{#a.b.c}}Here{{/a.b.c}}" == ""`, nil, `"" == ""`},
+		{"Dotted Names - Broken Chains", m{"a": m{}}, `"{{#a.b.c}}Here{{/a.b.c}}" == ""`, nil, `"" == ""`},
+		{"Surrounding Whitespace", m{"boolean": true}, " | {{#boolean}}\t|\t{{/boolean}} | \n", nil, " | \t|\t | \n"},
+		{"Internal Whitespace", m{"boolean": true}, " | {{#boolean}} {{! Important Whitespace }}\n {{/boolean}} | \n", nil,
+			" |  \n  | \n"},
+		{"Indented Inline Sections", m{"boolean": true}, " {{#boolean}}YES{{/boolean}}\n {{#boolean}}GOOD{{/boolean}}\n", nil,
+			" YES\n GOOD\n"},
+		{"Standalone Lines", m{"boolean": true}, "| This Is\n{{#boolean}}\n|\n{{/boolean}}\n| A Line\n", nil,
+			"| This Is\n|\n| A Line\n"},
+		{"Indented Standalone Lines", m{"boolean": true}, "| This Is\n  {{#boolean}}\n|\n  {{/boolean}}\n| A Line\n", nil,
+			"| This Is\n|\n| A Line\n"},
+		{"Standalone Line Endings", m{"boolean": true}, "|\r\n{{#boolean}}\r\n{{/boolean}}\r\n|", nil, "|\r\n|"},
+		{"Standalone Without Previous Line", m{"boolean": true}, "  {{#boolean}}\n#{{/boolean}}\n/", nil, "#\n/"},
+		{"Standalone Without Newline", m{"boolean": true}, "#{{#boolean}}\n/\n  {{/boolean}}", nil, "#\n/\n"},
+		{"Padding", m{"boolean": true}, "|{{# boolean }}={{/ boolean }}|", nil, "|=|"},
+	})
+}
+
+func TestSpecInverted(t *testing.T) {
+	runSpec(t, []specCase{
+		{"Falsey", m{"boolean": false}, `"{{^boolean}}This should be rendered.{{/boolean}}"`, nil, `"This should be rendered."`},
+		{"Truthy", m{"boolean": true}, `"{{^boolean}}This should not be rendered.{{/boolean}}"`, nil, `""`},
+		{"Null is falsey", m{"null": nil}, `"{{^null}}This should be rendered.{{/null}}"`, nil, `"This should be rendered."`},
+		{"Context", m{"context": m{"name": "Joe"}}, `"{{^context}}Hi {{name}}.{{/context}}"`, nil, `""`},
+		{"List", m{"list": []any{m{"n": 1}, m{"n": 2}, m{"n": 3}}}, `"{{^list}}{{n}}{{/list}}"`, nil, `""`},
+		{"Empty List", m{"list": []any{}}, `"{{^list}}Yay lists!{{/list}}"`, nil, `"Yay lists!"`},
+		{"Doubled", m{"bool": false, "two": "second"},
+			"{{^bool}}\n* first\n{{/bool}}\n* {{two}}\n{{^bool}}\n* third\n{{/bool}}\n", nil, "* first\n* second\n* third\n"},
+		{"Nested (Falsey)", m{"bool": false}, "| A {{^bool}}B {{^bool}}C{{/bool}} D{{/bool}} E |", nil, "| A B C D E |"},
+		{"Nested (Truthy)", m{"bool": true}, "| A {{^bool}}B {{^bool}}C{{/bool}} D{{/bool}} E |", nil, "| A  E |"},
+		{"Context Misses", m{}, "[{{^missing}}Found key 'missing'!{{/missing}}]", nil, "[Found key 'missing'!]"},
+		{"Dotted Names - Truthy", m{"a": m{"b": m{"c": true}}}, `"{{^a.b.c}}Not Here{{/a.b.c}}" == ""`, nil, `"" == ""`},
+		{"Dotted Names - Falsey", m{"a": m{"b": m{"c": false}}}, `"{{^a.b.c}}Not Here{{/a.b.c}}" == "Not Here"`, nil,
+			`"Not Here" == "Not Here"`},
+		{"Dotted Names - Broken Chains", m{"a": m{}}, `"{{^a.b.c}}Not Here{{/a.b.c}}" == "Not Here"`, nil,
+			`"Not Here" == "Not Here"`},
+		{"Surrounding Whitespace", m{"boolean": false}, " | {{^boolean}}\t|\t{{/boolean}} | \n", nil, " | \t|\t | \n"},
+		{"Internal Whitespace", m{"boolean": false}, " | {{^boolean}} {{! Important Whitespace }}\n {{/boolean}} | \n", nil,
+			" |  \n  | \n"},
+		{"Indented Inline Sections", m{"boolean": false}, " {{^boolean}}NO{{/boolean}}\n {{^boolean}}WAY{{/boolean}}\n", nil,
+			" NO\n WAY\n"},
+		{"Standalone Lines", m{"boolean": false}, "| This Is\n{{^boolean}}\n|\n{{/boolean}}\n| A Line\n", nil,
+			"| This Is\n|\n| A Line\n"},
+		{"Standalone Indented Lines", m{"boolean": false}, "| This Is\n  {{^boolean}}\n|\n  {{/boolean}}\n| A Line\n", nil,
+			"| This Is\n|\n| A Line\n"},
+		{"Standalone Line Endings", m{"boolean": false}, "|\r\n{{^boolean}}\r\n{{/boolean}}\r\n|", nil, "|\r\n|"},
+		{"Standalone Without Previous Line", m{"boolean": false}, "  {{^boolean}}\n^{{/boolean}}\n/", nil, "^\n/"},
+		{"Standalone Without Newline", m{"boolean": false}, "^{{^boolean}}\n/\n  {{/boolean}}", nil, "^\n/\n"},
+		{"Padding", m{"boolean": false}, "|{{^ boolean }}={{/ boolean }}|", nil, "|=|"},
+	})
+}
+
+func TestSpecComments(t *testing.T) {
+	runSpec(t, []specCase{
+		{"Inline", m{}, "12345{{! Comment Block! }}67890", nil, "1234567890"},
+		{"Multiline", m{}, "12345{{!\n  This is a\n  multi-line comment...\n}}67890\n", nil, "1234567890\n"},
+		{"Standalone", m{}, "Begin.\n{{! Comment Block! }}\nEnd.\n", nil, "Begin.\nEnd.\n"},
+		{"Indented Standalone", m{}, "Begin.\n  {{! Indented Comment Block! }}\nEnd.\n", nil, "Begin.\nEnd.\n"},
+		{"Standalone Line Endings", m{}, "|\r\n{{! Standalone Comment }}\r\n|", nil, "|\r\n|"},
+		{"Standalone Without Previous Line", m{}, "  {{! I'm Still Standalone }}\n!", nil, "!"},
+		{"Standalone Without Newline", m{}, "!\n  {{! I'm Still Standalone }}", nil, "!\n"},
+		{"Multiline Standalone", m{}, "Begin.\n{{!\nSomething's going on here...\n}}\nEnd.\n", nil, "Begin.\nEnd.\n"},
+		{"Indented Multiline Standalone", m{}, "Begin.\n  {{!\n    Something's going on here...\n  }}\nEnd.\n", nil,
+			"Begin.\nEnd.\n"},
+		{"Indented Inline", m{}, "  12 {{! 34 }}\n", nil, "  12 \n"},
+		{"Surrounding Whitespace", m{}, "12345 {{! Comment Block! }} 67890", nil, "12345  67890"},
+		{"Variable Name Collision", m{"! comment": 1, "! comment ": 2, "!comment": 3, "comment": 4},
+			"comments never show: >{{! comment }}<", nil, "comments never show: ><"},
+	})
+}
+
+func TestSpecDelimiters(t *testing.T) {
+	runSpec(t, []specCase{
+		{"Pair Behavior", m{"text": "Hey!"}, "{{=<% %>=}}(<%text%>)", nil, "(Hey!)"},
+		{"Special Characters", m{"text": "It worked!"}, "({{=[ ]=}}[text])", nil, "(It worked!)"},
+		{"Sections", m{"section": true, "data": "I got interpolated."},
+			"[\n{{#section}}\n  {{data}}\n  |data|\n{{/section}}\n\n{{= | | =}}\n|#section|\n  {{data}}\n  |data|\n|/section|\n]\n", nil,
+			"[\n  I got interpolated.\n  |data|\n\n  {{data}}\n  I got interpolated.\n]\n"},
+		{"Inverted Sections", m{"section": false, "data": "I got interpolated."},
+			"[\n{{^section}}\n  {{data}}\n  |data|\n{{/section}}\n\n{{= | | =}}\n|^section|\n  {{data}}\n  |data|\n|/section|\n]\n", nil,
+			"[\n  I got interpolated.\n  |data|\n\n  {{data}}\n  I got interpolated.\n]\n"},
+		{"Partial Inheritence", m{"value": "yes"}, "[ {{>include}} ]\n{{= | | =}}\n[ |>include| ]\n",
+			map[string]string{"include": ".{{value}}."}, "[ .yes. ]\n[ .yes. ]\n"},
+		{"Post-Partial Behavior", m{"value": "yes"}, "[ {{>include}} ]\n[ .{{value}}.  .|value|. ]\n",
+			map[string]string{"include": ".{{value}}. {{= | | =}} .|value|."}, "[ .yes.  .yes. ]\n[ .yes.  .|value|. ]\n"},
+		{"Surrounding Whitespace", m{}, "| {{=@ @=}} |", nil, "|  |"},
+		{"Outlying Whitespace (Inline)", m{}, " | {{=@ @=}}\n", nil, " | \n"},
+		{"Standalone Tag", m{}, "Begin.\n{{=@ @=}}\nEnd.\n", nil, "Begin.\nEnd.\n"},
+		{"Indented Standalone Tag", m{}, "Begin.\n  {{=@ @=}}\nEnd.\n", nil, "Begin.\nEnd.\n"},
+		{"Standalone Line Endings", m{}, "|\r\n{{= @ @ =}}\r\n|", nil, "|\r\n|"},
+		{"Standalone Without Previous Line", m{}, "  {{=@ @=}}\n=", nil, "="},
+		{"Standalone Without Newline", m{}, "=\n  {{=@ @=}}", nil, "=\n"},
+		{"Pair with Padding", m{}, "|{{= @   @ =}}|", nil, "||"},
+	})
+}
+
+func TestSpecPartials(t *testing.T) {
+	runSpec(t, []specCase{
+		{"Basic Behavior", m{}, `"{{>text}}"`, map[string]string{"text": "from partial"}, `"from partial"`},
+		{"Failed Lookup", m{}, `"{{>text}}"`, nil, `""`},
+		{"Context", m{"text": "content"}, `"{{>partial}}"`, map[string]string{"partial": "*{{text}}*"}, `"*content*"`},
+		{"Recursion", m{"content": "X", "nodes": []any{m{"content": "Y", "nodes": []any{}}}}, "{{>node}}",
+			map[string]string{"node": "{{content}}<{{#nodes}}{{>node}}{{/nodes}}>"}, "X<Y<>>"},
+		{"Nested", m{"a": "hello", "b": "world"}, "{{>outer}}",
+			map[string]string{"outer": "*{{a}} {{>inner}}*", "inner": "{{b}}!"}, "*hello world!*"},
+		{"Surrounding Whitespace", m{}, "| {{>partial}} |", map[string]string{"partial": "\t|\t"}, "| \t|\t |"},
+		{"Inline Indentation", m{"data": "|"}, "  {{data}}  {{> partial}}\n", map[string]string{"partial": ">\n>"},
+			"  |  >\n>\n"},
+		{"Standalone Line Endings", m{}, "|\r\n{{>partial}}\r\n|", map[string]string{"partial": ">"}, "|\r\n>|"},
+		{"Standalone Without Previous Line", m{}, "  {{>partial}}\n>", map[string]string{"partial": ">\n>"}, "  >\n  >>"},
+		{"Standalone Without Newline", m{}, ">\n  {{>partial}}", map[string]string{"partial": ">\n>"}, ">\n  >\n  >"},
+		{"Standalone Indentation", m{"content": "<\n->"}, "\\\n {{>partial}}\n/\n",
+			map[string]string{"partial": "|\n{{{content}}}\n|\n"}, "\\\n |\n <\n->\n |\n/\n"},
+		{"Padding Whitespace", m{"boolean": true}, "|{{> partial }}|", map[string]string{"partial": "[]"}, "|[]|"},
+	})
+}
+
+// TestRenderStringErrors checks that strict rendering reports undefined
+// variables and unbalanced sections with their line
+func TestRenderStringErrors(t *testing.T) {
+	data := map[string]string{"base00-hex": "1d2021", "scheme-is-dark-variant": "true"}
+	cases := []struct {
+		name, template, want string
+	}{
+		{"undefined variable", "a {{base00-hex}}\nb {{base0X-hex}}\n", `render template: line 2: undefined variable "base0X-hex"`},
+		{"undefined in section", "{{#scheme-is-dark-variant}}\n\n{{nope}}\n{{/scheme-is-dark-variant}}\n", `line 3: undefined variable "nope"`},
+		{"after multiline comment", "{{!\n\n}}{{nope}}", `line 3: undefined variable "nope"`},
+		{"undefined raw", "x\n{{{nope}}}", `line 2: undefined variable "nope"`},
+		{"unclosed section", "x\n{{#scheme-is-dark-variant}}\ny\n", `parse template: line 2: section "scheme-is-dark-variant" is never closed`},
+		{"mismatched close", "{{#a}}\n{{/b}}\n", `line 2: {{/b}} closes section "a" opened on line 1`},
+		{"close without open", "x\n\n{{/a}}", `line 3: {{/a}} without an open section`},
+		{"unclosed tag", "x\n{{base00-hex", `line 2: unclosed tag {{`},
+		{"missing partial", "x\n{{>nope}}", `line 2: partial "nope" not found`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := RenderString(c.template, data)
+			if err == nil {
+				t.Fatalf("expected an error containing %q", c.want)
+			}
+			if !strings.Contains(err.Error(), c.want) {
+				t.Errorf("error %q does not contain %q", err, c.want)
+			}
+		})
+	}
+
+	// Sections over missing keys, "false" and "" are skipped, not errors
+	out, err := RenderString("{{#missing}}{{nope}}{{/missing}}{{^missing}}ok{{/missing}}", data)
+	if err != nil || out != "ok" {
+		t.Errorf("got %q, %v; want \"ok\"", out, err)
+	}
+}
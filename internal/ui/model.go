@@ -1,9 +1,11 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"io"
-	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/list"
@@ -11,6 +13,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/jaycee1285/base16changer/internal/palette"
 	"github.com/jaycee1285/base16changer/internal/scheme"
 	"github.com/jaycee1285/base16changer/internal/targets"
 )
@@ -32,7 +35,10 @@ const (
 
 var tabNames = []string{"Schemes", "Icons", "Wallpapers"}
 
-type item struct{ title string }
+type item struct {
+	title string
+	note  string // shown dimmed after the title, e.g. near-search distances
+}
 
 func (i item) Title() string       { return i.title }
 func (i item) Description() string { return "" }
@@ -57,13 +63,17 @@ func (d compactDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd { return nil
 
 func (d compactDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
 	it, _ := listItem.(item)
+	note := ""
+	if it.note != "" {
+		note = "  " + dimStyle.Render(it.note)
+	}
 	if index == m.Index() {
 		line := "  ▸ " + it.title
-		fmt.Fprint(w, d.focused.Render(line))
+		fmt.Fprint(w, d.focused.Render(line)+note)
 		return
 	}
 	line := "    " + it.title
-	fmt.Fprint(w, d.normal.Render(line))
+	fmt.Fprint(w, d.normal.Render(line)+note)
 }
 
 type dataLoadedMsg struct {
@@ -72,7 +82,28 @@ type dataLoadedMsg struct {
 	walls   []string
 }
 
-type applyDoneMsg struct{ err error }
+type contrastMsg struct {
+	scheme string
+	report *targets.ContrastReport
+	err    error
+}
+
+type wallpaperSchemeMsg struct {
+	wallpaper string
+	scheme    string
+	err       error
+}
+
+type nearMsg struct {
+	query   string
+	matches []targets.NearMatch
+	err     error
+}
+
+type applyDoneMsg struct {
+	report *targets.Report
+	err    error
+}
 
 // Selections tracks what the user has chosen
 type Selections struct {
@@ -94,11 +125,20 @@ type Model struct {
 	icons   []string
 	walls   []string
 
-	cfg      *targets.Config
-	selected Selections
-	status   string
-	applying bool
-	loaded   bool
+	cfg         *targets.Config
+	selected    Selections
+	report      *targets.Report         // result of the last apply
+	contrast    *targets.ContrastReport // contrast audit of the selected scheme
+	contrastErr error
+	status      string
+	applying    bool
+	loaded      bool
+
+	// Similarity filter of the scheme list: near is the active query,
+	// nearInput the one being typed while nearEditing
+	near        string
+	nearInput   string
+	nearEditing bool
 }
 
 func New(cfg *targets.Config) Model {
@@ -134,18 +174,10 @@ func (m Model) Init() tea.Cmd {
 
 func loadDataCmd(cfg *targets.Config) tea.Cmd {
 	return func() tea.Msg {
-		var schemes []string
-		if cfg.SchemesDir != "" {
-			// CLI override - use single directory
-			schemes, _ = targets.ScanSchemesDir(cfg.SchemesDir)
-		} else {
-			// Default - scan all standard locations
-			schemes = targets.ScanAllSchemes()
-		}
 		return dataLoadedMsg{
-			schemes: schemes,
-			icons:   targets.ScanIconThemes(),
-			walls:   targets.ScanWallpapers(),
+			schemes: cfg.ScanSchemes(),
+			icons:   targets.ScanIconThemes(cfg.IconDirs),
+			walls:   targets.ScanWallpapers(cfg.WallpaperDir),
 		}
 	}
 }
@@ -174,18 +206,60 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.lists[tabWalls] = rebuildList(m.lists[tabWalls], msg.walls)
 		return m, nil
 
-	case applyDoneMsg:
+	case contrastMsg:
+		if msg.scheme == m.selected.Scheme {
+			m.contrast, m.contrastErr = msg.report, msg.err
+		}
+		return m, nil
+
+	case wallpaperSchemeMsg:
 		m.applying = false
 		if msg.err != nil {
+			m.status = "Generate failed: " + firstLine(msg.err.Error())
+			return m, nil
+		}
+		if !contains(m.schemes, msg.scheme) {
+			m.schemes = append(m.schemes, msg.scheme)
+			sort.Strings(m.schemes)
+			m.near = ""
+			m.lists[tabSchemes] = rebuildList(m.lists[tabSchemes], m.schemes)
+		}
+		m.selected.Scheme, m.selected.Wallpaper = msg.scheme, msg.wallpaper
+		m.contrast, m.contrastErr = nil, nil
+		m.status = "Generated " + msg.scheme + " from " + msg.wallpaper + " (A to apply)"
+		return m, contrastCmd(m.cfg, msg.scheme)
+
+	case nearMsg:
+		m.applying = false
+		if msg.err != nil {
+			m.status = firstLine(msg.err.Error())
+			return m, nil
+		}
+		m.near = msg.query
+		m.lists[tabSchemes] = rebuildNearList(m.lists[tabSchemes], msg.matches)
+		m.status = fmt.Sprintf("%d schemes by distance to %s (N to edit)", len(msg.matches), msg.query)
+		return m, nil
+
+	case applyDoneMsg:
+		m.applying = false
+		m.report = msg.report
+		switch {
+		case msg.err != nil:
 			m.status = "Apply failed: " + firstLine(msg.err.Error())
-		} else {
-			m.status = "Applied successfully!"
+		case msg.report.Failed():
+			m.status = "Applied with errors: " + msg.report.Summary()
+		default:
+			m.status = "Applied: " + msg.report.Summary()
 		}
 		return m, nil
 
 	case tea.KeyMsg:
 		k := msg.String()
 
+		if m.nearEditing {
+			return m.updateNearInput(msg)
+		}
+
 		// Global keys
 		switch k {
 		case "ctrl+c", "q":
@@ -204,12 +278,29 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Navigation depends on whether we're in a list or at panel titles
 		if m.inList && m.expanded >= 0 {
+			if k == "s" && m.expanded == tabWalls && m.lists[tabWalls].FilterState() != list.Filtering {
+				it, ok := m.lists[tabWalls].SelectedItem().(item)
+				if !ok || m.applying {
+					return m, nil
+				}
+				m.applying = true
+				m.status = "Generating scheme from " + it.title + "…"
+				return m, tea.Batch(m.spinner.Tick, wallpaperSchemeCmd(m.cfg, it.title))
+			}
+			if k == "n" && m.expanded == tabSchemes && m.lists[tabSchemes].FilterState() != list.Filtering && !m.applying {
+				m.nearEditing = true
+				m.nearInput = m.near
+				return m, nil
+			}
 			switch k {
 			case "left", "esc":
 				m.inList = false
 				return m, nil
 			case "enter":
 				m = m.selectCurrentItem()
+				if m.expanded == tabSchemes && m.selected.Scheme != "" {
+					return m, contrastCmd(m.cfg, m.selected.Scheme)
+				}
 				return m, nil
 			case "up", "down", "j", "k", "pgup", "pgdown", "home", "end":
 				l := m.lists[m.expanded]
@@ -259,32 +350,118 @@ func rebuildList(l list.Model, items []string) list.Model {
 	return l
 }
 
-func applyCmd(cfg *targets.Config, sel Selections) tea.Cmd {
-	return func() tea.Msg {
-		// Find scheme path
-		var schemePath string
-		var err error
-		if cfg.SchemesDir != "" {
-			// Try both extensions
-			for _, ext := range []string{".yaml", ".yml"} {
-				path := cfg.SchemesDir + "/" + sel.Scheme + ext
-				if _, statErr := os.Stat(path); statErr == nil {
-					schemePath = path
-					break
-				}
-			}
-			if schemePath == "" {
-				schemePath = cfg.SchemesDir + "/" + sel.Scheme + ".yaml" // fallback
+// updateNearInput edits the near-search query. Enter runs it (an empty
+// query restores the alphabetical list), Esc leaves it unchanged.
+func (m Model) updateNearInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.nearEditing = false
+	case tea.KeyEnter:
+		m.nearEditing = false
+		query := strings.TrimSpace(m.nearInput)
+		if query == "" {
+			m.near = ""
+			m.lists[tabSchemes] = rebuildList(m.lists[tabSchemes], m.schemes)
+			m.status = "Near filter cleared"
+			return m, nil
+		}
+		m.applying = true
+		m.status = "Ranking schemes…"
+		return m, tea.Batch(m.spinner.Tick, nearCmd(m.cfg, m.schemes, query))
+	case tea.KeyBackspace:
+		if r := []rune(m.nearInput); len(r) > 0 {
+			m.nearInput = string(r[:len(r)-1])
+		}
+	case tea.KeySpace:
+		m.nearInput += " "
+	case tea.KeyRunes:
+		m.nearInput += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// rebuildNearList fills the scheme list in ranked order, noting the mean
+// distance and the distance of each searched color
+func rebuildNearList(l list.Model, matches []targets.NearMatch) list.Model {
+	lis := make([]list.Item, 0, len(matches))
+	for _, nm := range matches {
+		note := fmt.Sprintf("ΔE %.1f", nm.Score)
+		if len(nm.Slots) > 1 {
+			var parts []string
+			for _, d := range nm.Slots {
+				parts = append(parts, fmt.Sprintf("%s %.1f", d.Slot, d.Distance))
 			}
-		} else {
-			schemePath, err = targets.FindScheme(sel.Scheme)
+			note += " (" + strings.Join(parts, ", ") + ")"
+		} else if len(nm.Slots) == 1 {
+			note += " " + nm.Slots[0].Slot
+		}
+		lis = append(lis, item{title: nm.Name, note: note})
+	}
+	l.SetItems(lis)
+	l.ResetSelected()
+	return l
+}
+
+// nearCmd ranks the schemes by distance to a query of space-separated
+// colors, each optionally pinned to a slot ("base00=#1d2021 #83a598")
+func nearCmd(cfg *targets.Config, schemes []string, query string) tea.Cmd {
+	return func() tea.Msg {
+		var colors []scheme.NearColor
+		for _, spec := range strings.Fields(query) {
+			n, err := scheme.ParseNear(spec)
 			if err != nil {
-				return applyDoneMsg{err: err}
+				return nearMsg{err: err}
 			}
+			colors = append(colors, n)
+		}
+		return nearMsg{query: query, matches: cfg.RankNear(schemes, colors)}
+	}
+}
+
+// contrastCmd audits the template color pairs of a scheme in the background
+func contrastCmd(cfg *targets.Config, name string) tea.Cmd {
+	return func() tea.Msg {
+		path, err := cfg.ResolveScheme(name)
+		if err != nil {
+			return contrastMsg{scheme: name, err: err}
+		}
+		s, err := cfg.ParseScheme(path)
+		if err != nil {
+			return contrastMsg{scheme: name, err: err}
+		}
+		return contrastMsg{scheme: name, report: targets.CheckContrast(s, targets.StandardWCAG)}
+	}
+}
+
+// wallpaperSchemeCmd extracts a scheme from a wallpaper and saves it to the
+// user scheme dir. An existing scheme with the same name is never replaced.
+func wallpaperSchemeCmd(cfg *targets.Config, wallpaper string) tea.Cmd {
+	return func() tea.Msg {
+		s, err := palette.FromWallpaper(filepath.Join(cfg.WallpaperDir, wallpaper), palette.VariantAuto)
+		if err != nil {
+			return wallpaperSchemeMsg{err: err}
+		}
+		_, err = cfg.SaveScheme(s, s.SlugName(), false)
+		if errors.Is(err, targets.ErrSchemeExists) {
+			err = fmt.Errorf("%s already exists; select it, or delete it to generate again", s.SlugName())
+		}
+		if err != nil {
+			return wallpaperSchemeMsg{err: err}
+		}
+		return wallpaperSchemeMsg{wallpaper: wallpaper, scheme: s.SlugName()}
+	}
+}
+
+func applyCmd(cfg *targets.Config, sel Selections) tea.Cmd {
+	return func() tea.Msg {
+		// Find scheme path
+		schemePath, err := cfg.ResolveScheme(sel.Scheme)
+		if err != nil {
+			return applyDoneMsg{err: err}
 		}
 
 		// Parse scheme
-		s, err := scheme.Parse(schemePath)
+		s, err := cfg.ParseScheme(schemePath)
 		if err != nil {
 			return applyDoneMsg{err: err}
 		}
@@ -294,8 +471,8 @@ func applyCmd(cfg *targets.Config, sel Selections) tea.Cmd {
 		cfg.Wallpaper = sel.Wallpaper
 
 		// Apply
-		err = targets.Apply(cfg, s)
-		return applyDoneMsg{err: err}
+		report, err := targets.Apply(cfg, s)
+		return applyDoneMsg{report: report, err: err}
 	}
 }
 
@@ -312,6 +489,7 @@ func (m Model) selectCurrentItem() Model {
 	switch m.expanded {
 	case tabSchemes:
 		m.selected.Scheme = it.title
+		m.contrast, m.contrastErr = nil, nil
 		m.status = "Scheme: " + it.title
 	case tabIcons:
 		m.selected.IconTheme = it.title
@@ -400,6 +578,12 @@ func (m Model) View() string {
 	b.WriteString(m.renderPanels())
 	b.WriteString("\n")
 
+	// Last apply report
+	if m.report != nil {
+		b.WriteString(m.renderReport())
+		b.WriteString("\n")
+	}
+
 	// Help commands
 	b.WriteString(m.renderHelp())
 	b.WriteString("\n")
@@ -436,10 +620,37 @@ func (m Model) renderSelections() string {
 		value := selValueStyle.Render(emptyDash(sel.value))
 		lines = append(lines, label+value)
 	}
+	if line := m.renderContrast(); line != "" {
+		lines = append(lines, selLabelStyle.Render("Contrast:")+line)
+	}
 
 	return strings.Join(lines, "\n")
 }
 
+// renderContrast summarizes the WCAG audit of the selected scheme, naming
+// the first failing pairs
+func (m Model) renderContrast() string {
+	switch {
+	case m.contrastErr != nil:
+		return reportFailStyle.Render("✗ " + firstLine(m.contrastErr.Error()))
+	case m.contrast == nil:
+		return ""
+	}
+	failures := m.contrast.Failures()
+	if len(failures) == 0 {
+		return reportOKStyle.Render("✓ passes WCAG AA")
+	}
+	var names []string
+	for _, f := range failures {
+		if len(names) == 2 {
+			names = append(names, "…")
+			break
+		}
+		names = append(names, f.Target+" "+f.Usage)
+	}
+	return reportFailStyle.Render(fmt.Sprintf("✗ %d low: %s", len(failures), strings.Join(names, ", ")))
+}
+
 func (m Model) renderPanels() string {
 	var lines []string
 	lines = append(lines, dimStyle.Render("─── Theme Panels ───"))
@@ -471,6 +682,15 @@ func (m Model) renderPanels() string {
 		line := prefix + indicator + style.Render(tabNames[t]) + countStr
 		lines = append(lines, line)
 
+		if isExpanded && t == tabSchemes {
+			switch {
+			case m.nearEditing:
+				lines = append(lines, "  "+helpKeyStyle.Render("Near: ")+m.nearInput+"█")
+				lines = append(lines, "  "+dimStyle.Render("slot=#hex or #hex, space-separated · Enter search · Esc cancel"))
+			case m.near != "":
+				lines = append(lines, "  "+dimStyle.Render("Near: "+m.near))
+			}
+		}
 		if isExpanded {
 			listView := m.lists[t].View()
 			indented := indentLines(listView, "  ")
@@ -481,6 +701,48 @@ func (m Model) renderPanels() string {
 	return strings.Join(lines, "\n")
 }
 
+var (
+	reportOKStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+	reportFailStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+)
+
+func (m Model) renderReport() string {
+	var lines []string
+	lines = append(lines, dimStyle.Render("─── Last Apply ───"))
+
+	// Compact status grid, then one line per failure with its error
+	var cells []string
+	for _, res := range m.report.Results {
+		var cell string
+		switch {
+		case res.Status == targets.StatusFailed || res.Reload == targets.StatusFailed:
+			cell = reportFailStyle.Render("✗ " + res.Target)
+		case res.Status == targets.StatusSkipped:
+			cell = dimStyle.Render("– " + res.Target)
+		default:
+			cell = reportOKStyle.Render("✓ " + res.Target)
+		}
+		cells = append(cells, lipgloss.NewStyle().Width(16).Render(cell))
+	}
+	for i := 0; i < len(cells); i += 4 {
+		end := i + 4
+		if end > len(cells) {
+			end = len(cells)
+		}
+		lines = append(lines, strings.Join(cells[i:end], ""))
+	}
+
+	for _, res := range m.report.Failures() {
+		msg := res.Error
+		if msg == "" {
+			msg = "reload: " + res.ReloadError
+		}
+		lines = append(lines, reportFailStyle.Render(res.Target+": "+firstLine(msg)))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
 var helpKeyStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("6")).Bold(true)
 var helpDescStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("7"))
 
@@ -496,6 +758,8 @@ func (m Model) renderHelp() string {
 		{"→ / Enter", "Expand panel"},
 		{"← / Esc", "Collapse panel"},
 		{"/", "Filter items"},
+		{"N", "Find schemes near colors"},
+		{"S", "Scheme from wallpaper"},
 		{"A", "Apply changes"},
 		{"Q", "Quit"},
 	}
@@ -531,6 +795,15 @@ func firstLine(s string) string {
 	return s
 }
 
+func contains(items []string, s string) bool {
+	for _, it := range items {
+		if it == s {
+			return true
+		}
+	}
+	return false
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a